@@ -62,6 +62,26 @@ type Closer interface {
 type Tasks struct {
 	Name string
 	sync.WaitGroup
+
+	// RecoverPanics recovers panics inside tasks, converting them into
+	// a *PanicError which is logged (and collected) like any other error.
+	RecoverPanics bool
+
+	// CollectErrors keeps the errors returned by tasks, other than
+	// context.Canceled, so that they can be retrieved with WaitErr.
+	CollectErrors bool
+
+	// ReportLeaks records the goroutine and the caller of every task, so
+	// that the error returned by Shutdown includes their stack and where
+	// they were started. It costs a stack walk per task.
+	ReportLeaks bool
+
+	// mu protects the fields below, which support Shutdown and WaitErr.
+	mu       sync.Mutex
+	shutdown bool
+	nextID   uint64
+	running  map[uint64]*runningTask
+	errs     []error
 }
 
 // NewTasks creates new instance of Tasks
@@ -73,41 +93,72 @@ func NewTasks(name string) *Tasks {
 //
 // It creates a new trace for the task and passes through deadlines.
 func (t *Tasks) Run(ctx context.Context, r Runner) {
+	callers := t.callers()
 	t.WaitGroup.Add(1)
 	go func() {
 		defer t.WaitGroup.Done()
-		ctx2 := trace.StartSpan(ctx, t.Name)
-		defer trace.End(ctx2)
-		if err := r.Run(ctx2); err != nil && !errors.Is(err, context.Canceled) {
-			log.Error(ctx2, t.Name, events.NewErrorInfo(err))
-		}
+		ctx, done := t.track(ctx, r, callers)
+		defer done()
+		t.runTask(ctx, r)
 	}()
 }
 
 // Loop repeatedly executes the provided task until it returns false
 // or the context is canceled.
 func (t *Tasks) Loop(ctx context.Context, r Runner) {
-	run := func(ctx context.Context) bool {
-		ctx2 := trace.StartSpan(ctx, t.Name)
-		defer trace.End(ctx2)
-		if err := r.Run(ctx2); err != nil && !errors.Is(err, context.Canceled) {
-			log.Error(ctx2, t.Name, events.NewErrorInfo(err))
-			return true
-		}
-		return false
-	}
-
+	callers := t.callers()
 	t.WaitGroup.Add(1)
 	go func() {
 		defer t.WaitGroup.Done()
+		ctx, done := t.track(ctx, r, callers)
+		defer done()
 		for ctx.Err() == nil {
-			if ok := run(ctx); ok {
+			if failed := t.runTask(ctx, r); failed {
 				break
 			}
 		}
 	}()
 }
 
+// WaitErr waits for all tasks to finish, like Wait, and returns the
+// errors collected from them joined with errors.Join. Errors are only
+// collected when CollectErrors is set.
+//
+//	tasks := async.Tasks{Name: "batch", RecoverPanics: true, CollectErrors: true}
+//	for _, item := range items {
+//	    tasks.Run(ctx, process(item))
+//	}
+//	if err := tasks.WaitErr(); err != nil {
+//	    return err
+//	}
+func (t *Tasks) WaitErr() error {
+	t.WaitGroup.Wait()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return errors.Join(t.errs...)
+}
+
+// runTask runs r once within its own span. It returns true if r
+// failed with an error other than context.Canceled.
+func (t *Tasks) runTask(ctx context.Context, r Runner) bool {
+	ctx2 := trace.StartSpan(ctx, t.Name)
+	defer trace.End(ctx2)
+	if t.RecoverPanics {
+		r = Recover(r)
+	}
+	if err := r.Run(ctx2); err != nil && !errors.Is(err, context.Canceled) {
+		log.Error(ctx2, t.Name, events.NewErrorInfo(err))
+		if t.CollectErrors {
+			t.mu.Lock()
+			t.errs = append(t.errs, err)
+			t.mu.Unlock()
+		}
+		return true
+	}
+	return false
+}
+
 // Default is the default runner
 var Default = NewTasks("async.run")
 
@@ -140,8 +191,25 @@ func RunClose(ctx context.Context, r Runner) error {
 
 // RunGroup runs a group of runner tasks and exits when the first run group errors out
 func RunGroup(rg []Runner) Runner {
+	return runGroup(rg, false)
+}
+
+// RunGroupCollect is like RunGroup but recovers panics inside the
+// runners, converting them into a *PanicError, and returns the errors
+// from all runners joined with errors.Join rather than just the first.
+// As with RunGroup, the first error cancels the remaining runners; the
+// resulting context.Canceled errors are not included.
+func RunGroupCollect(rg []Runner) Runner {
+	return runGroup(rg, true)
+}
+
+func runGroup(rg []Runner, collect bool) Runner {
 	ru := Func(func(ctx context.Context) error {
 		g, ctx := errgroup.WithContext(ctx)
+		var (
+			mu   sync.Mutex
+			errs []error
+		)
 		for idx := range rg {
 			r := rg[idx]
 			g.Go(func() error {
@@ -151,10 +219,24 @@ func RunGroup(rg []Runner) Runner {
 					}
 				}()
 
-				return r.Run(ctx)
+				if !collect {
+					return r.Run(ctx)
+				}
+
+				err := Recover(r).Run(ctx)
+				if err != nil && !errors.Is(err, context.Canceled) {
+					mu.Lock()
+					errs = append(errs, err)
+					mu.Unlock()
+				}
+				return err
 			})
 		}
-		return g.Wait()
+		err := g.Wait()
+		if len(errs) > 0 {
+			return errors.Join(errs...)
+		}
+		return err
 	})
 	return ru
 }
@@ -194,12 +276,13 @@ func SleepUntil(ctx context.Context, deadline time.Time) {
 // Note that unlike the `sync.Locker` style of mutex, this one's `Lock` method
 // can fail and you must check its return value.
 type MutexWithContext struct {
-	sem *semaphore.Weighted
+	sem  *semaphore.Weighted
+	opts syncOptions
 }
 
 // NewMutexWithContext creates a new MutexWithContext instance.
-func NewMutexWithContext() *MutexWithContext {
-	return &MutexWithContext{semaphore.NewWeighted(1)}
+func NewMutexWithContext(options ...SyncOption) *MutexWithContext {
+	return &MutexWithContext{sem: semaphore.NewWeighted(1), opts: newSyncOptions(options)}
 }
 
 // Lock acquires the mutex, blocking if it is unavailable.
@@ -207,7 +290,10 @@ func NewMutexWithContext() *MutexWithContext {
 // Unlike `sync.Mutex.Lock()`, this function can fail.  It is responsibility of
 // the caller to check the returned error and not proceed if it is non-nil.
 func (m *MutexWithContext) Lock(ctx context.Context) error {
-	return m.sem.Acquire(ctx, 1)
+	start := time.Now()
+	err := m.sem.Acquire(ctx, 1)
+	m.opts.observe("mutex", start, err)
+	return err
 }
 
 // Unlock releases the mutex, allowing the next waiter to proceed.
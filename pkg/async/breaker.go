@@ -0,0 +1,421 @@
+// Copyright 2026 Outreach Corporation. All Rights Reserved.
+
+// Description: Provides a circuit breaker for outbound calls
+
+package async
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/getoutreach/gobox/pkg/log"
+	"github.com/getoutreach/gobox/pkg/orerr"
+	"github.com/getoutreach/gobox/pkg/statuscodes"
+	"github.com/getoutreach/gobox/pkg/trace"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// breakerState registers the async_circuit_breaker_state metric for
+// reporting the current state of circuit breakers (0 closed, 1 half
+// open, 2 open).
+var breakerState = promauto.NewGaugeVec( // nolint:gochecknoglobals // Why: cleaner to use everywhere
+	prometheus.GaugeOpts{
+		Name: "async_circuit_breaker_state",
+		Help: "The state of the circuit breaker: 0 closed, 1 half open, 2 open",
+	},
+	[]string{"name"}, // Labels
+)
+
+// breakerTransitions registers the async_circuit_breaker_transitions_total
+// metric for counting circuit breaker state changes.
+var breakerTransitions = promauto.NewCounterVec( // nolint:gochecknoglobals // Why: cleaner to use everywhere
+	prometheus.CounterOpts{
+		Name: "async_circuit_breaker_transitions_total",
+		Help: "The number of circuit breaker state transitions",
+	},
+	[]string{"name", "from", "to"}, // Labels
+)
+
+// breakerRejected registers the async_circuit_breaker_rejected_total
+// metric for counting calls rejected by circuit breakers.
+var breakerRejected = promauto.NewCounterVec( // nolint:gochecknoglobals // Why: cleaner to use everywhere
+	prometheus.CounterOpts{
+		Name: "async_circuit_breaker_rejected_total",
+		Help: "The number of calls rejected because the circuit breaker was open",
+	},
+	[]string{"name"}, // Labels
+)
+
+// ErrCircuitOpen is wrapped by the errors returned when a circuit
+// breaker rejects a call. The returned errors carry the
+// statuscodes.Unavailable status.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitState is the state of a CircuitBreaker
+type CircuitState int
+
+const (
+	// CircuitClosed lets all calls through while tracking failures
+	CircuitClosed CircuitState = iota
+
+	// CircuitHalfOpen lets a limited number of probe calls through to
+	// find out whether the dependency recovered
+	CircuitHalfOpen
+
+	// CircuitOpen rejects all calls until the open timeout expires
+	CircuitOpen
+)
+
+// String returns the name of the state
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitHalfOpen:
+		return "half-open"
+	case CircuitOpen:
+		return "open"
+	default:
+		return fmt.Sprintf("CircuitState(%d)", int(s))
+	}
+}
+
+// BreakerOptions configures a CircuitBreaker
+type BreakerOptions struct {
+	// Window is the rolling window failures are counted over
+	Window time.Duration
+
+	// Buckets is the number of buckets the window is split into. Calls
+	// leave the window one bucket at a time.
+	Buckets int
+
+	// FailureRatio is the ratio of failed calls within the window above
+	// which the breaker opens
+	FailureRatio float64
+
+	// MinRequests is the minimum number of calls within the window
+	// before the failure ratio is considered
+	MinRequests int
+
+	// OpenTimeout is how long the breaker stays open before letting
+	// probe calls through
+	OpenTimeout time.Duration
+
+	// HalfOpenCalls is the number of concurrent probe calls allowed in
+	// the half-open state. The breaker closes once that many probes
+	// succeeded and opens again as soon as one fails.
+	HalfOpenCalls int
+
+	// IsFailure decides whether an error counts as a failure. Defaults
+	// to IsServerError.
+	IsFailure func(err error) bool
+}
+
+// BreakerOption configures a CircuitBreaker
+type BreakerOption func(*BreakerOptions)
+
+// WithFailureRatio opens the breaker when more than ratio of the calls
+// made within the rolling window failed, once at least minRequests
+// calls were made. A non-positive window is replaced by a minute.
+func WithFailureRatio(ratio float64, minRequests int, window time.Duration) BreakerOption {
+	return func(opts *BreakerOptions) {
+		opts.FailureRatio = ratio
+		opts.MinRequests = minRequests
+		opts.Window = window
+	}
+}
+
+// WithOpenTimeout sets how long the breaker stays open before it lets
+// halfOpenCalls probe calls through
+func WithOpenTimeout(d time.Duration, halfOpenCalls int) BreakerOption {
+	return func(opts *BreakerOptions) {
+		opts.OpenTimeout = d
+		opts.HalfOpenCalls = halfOpenCalls
+	}
+}
+
+// WithFailureFunc sets the function deciding whether an error counts as
+// a failure
+func WithFailureFunc(fn func(err error) bool) BreakerOption {
+	return func(opts *BreakerOptions) {
+		opts.IsFailure = fn
+	}
+}
+
+// IsServerError reports whether err is a server error according to
+// orerr.ExtractErrorStatusCategory. Errors without a status code are
+// server errors, except for context.Canceled which means the caller
+// gave up.
+func IsServerError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	return orerr.ExtractErrorStatusCategory(err) == statuscodes.CategoryServerError
+}
+
+// breakerBucket counts the calls made during one slice of the window
+type breakerBucket struct {
+	start    time.Time
+	total    int
+	failures int
+}
+
+// CircuitBreaker stops calling a failing dependency for a while,
+// failing fast instead.
+//
+// While closed, calls go through and the breaker counts failures over
+// a rolling window; only server errors (see IsServerError) count, so
+// bad requests do not trip the breaker. Once the failure ratio exceeds
+// the threshold, the breaker opens and calls fail right away with an
+// error wrapping ErrCircuitOpen with the statuscodes.Unavailable status.
+// After the open timeout, a few probe calls are let through: the
+// breaker closes if they succeed and opens again otherwise.
+//
+// State transitions are logged and reported in the
+// async_circuit_breaker_state and async_circuit_breaker_transitions_total
+// metrics.
+//
+//	cb := async.NewCircuitBreaker("billing-api",
+//	    async.WithFailureRatio(0.5, 20, time.Minute),
+//	)
+//	err := cb.Execute(ctx, func(ctx context.Context) error {
+//	    return client.Charge(ctx, req)
+//	})
+type CircuitBreaker struct {
+	Name string
+
+	opts *BreakerOptions
+
+	mu         sync.Mutex
+	state      CircuitState
+	generation uint64
+	openedAt   time.Time
+	buckets    []breakerBucket
+	probes     int
+	successes  int
+}
+
+// NewCircuitBreaker creates a closed CircuitBreaker.
+//
+// By default, the breaker opens when more than half of at least 10
+// calls within a minute failed, and stays open for 30 seconds.
+func NewCircuitBreaker(name string, options ...BreakerOption) *CircuitBreaker {
+	opts := &BreakerOptions{
+		Window:        time.Minute,
+		Buckets:       10,
+		FailureRatio:  0.5,
+		MinRequests:   10,
+		OpenTimeout:   30 * time.Second,
+		HalfOpenCalls: 1,
+		IsFailure:     IsServerError,
+	}
+	for _, o := range options {
+		o(opts)
+	}
+	if opts.Window <= 0 {
+		opts.Window = time.Minute
+	}
+	opts.Buckets = max(opts.Buckets, 1)
+	opts.HalfOpenCalls = max(opts.HalfOpenCalls, 1)
+
+	cb := &CircuitBreaker{Name: name, opts: opts, buckets: make([]breakerBucket, opts.Buckets)}
+	breakerState.WithLabelValues(name).Set(float64(CircuitClosed))
+	return cb
+}
+
+// State returns the current state of the breaker
+func (cb *CircuitBreaker) State() CircuitState {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.expireOpen(context.Background(), time.Now())
+	return cb.state
+}
+
+// Execute calls fn unless the breaker is open, in which case an error
+// wrapping ErrCircuitOpen with the statuscodes.Unavailable status is
+// returned. The error returned by fn is recorded and returned as is.
+// A panic in fn is recorded as a failure before it propagates.
+func (cb *CircuitBreaker) Execute(ctx context.Context, fn func(ctx context.Context) error) error {
+	generation, err := cb.before(ctx)
+	if err != nil {
+		return err
+	}
+
+	// failed stays true if fn panics, so that a half-open probe is
+	// always released
+	failed := true
+	defer func() {
+		cb.after(ctx, generation, failed)
+	}()
+
+	err = fn(ctx)
+	failed = err != nil && cb.opts.IsFailure(err)
+	return err
+}
+
+// ExecuteValue is Execute for functions returning a value
+func ExecuteValue[T any](ctx context.Context, cb *CircuitBreaker, fn func(ctx context.Context) (T, error)) (T, error) {
+	var val T
+	err := cb.Execute(ctx, func(ctx context.Context) error {
+		var err error
+		val, err = fn(ctx)
+		return err
+	})
+	return val, err
+}
+
+// Wrap returns a Runner which runs r through the breaker
+func (cb *CircuitBreaker) Wrap(r Runner) Runner {
+	return &breakerRunner{Runner: r, cb: cb}
+}
+
+// before checks whether a call may go through and returns the
+// generation the call belongs to
+func (cb *CircuitBreaker) before(ctx context.Context) (uint64, error) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.expireOpen(ctx, time.Now())
+	switch cb.state {
+	case CircuitOpen:
+		return 0, cb.reject(ctx)
+	case CircuitHalfOpen:
+		if cb.probes >= cb.opts.HalfOpenCalls {
+			return 0, cb.reject(ctx)
+		}
+		cb.probes++
+	case CircuitClosed:
+	}
+	return cb.generation, nil
+}
+
+// after records the outcome of a call
+func (cb *CircuitBreaker) after(ctx context.Context, generation uint64, failed bool) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	// the state changed while the call was running, its outcome says
+	// nothing about the current state
+	if generation != cb.generation {
+		return
+	}
+
+	now := time.Now()
+	switch cb.state {
+	case CircuitClosed:
+		b := cb.bucket(now)
+		b.total++
+		if failed {
+			b.failures++
+		}
+		if total, failures := cb.counts(now); total >= cb.opts.MinRequests &&
+			float64(failures)/float64(total) > cb.opts.FailureRatio {
+			cb.transition(ctx, CircuitOpen, now)
+		}
+	case CircuitHalfOpen:
+		cb.probes--
+		if failed {
+			cb.transition(ctx, CircuitOpen, now)
+			return
+		}
+		cb.successes++
+		if cb.successes >= cb.opts.HalfOpenCalls {
+			cb.transition(ctx, CircuitClosed, now)
+		}
+	case CircuitOpen:
+	}
+}
+
+// reject records a rejected call and returns its error, cb.mu must be
+// held
+func (cb *CircuitBreaker) reject(ctx context.Context) error {
+	breakerRejected.WithLabelValues(cb.Name).Inc()
+	trace.SendEvent(ctx, "async.breaker rejected", log.F{
+		"breaker": cb.Name,
+		"state":   cb.state.String(),
+	})
+	return orerr.NewErrorStatus(fmt.Errorf("%s: %w", cb.Name, ErrCircuitOpen), statuscodes.Unavailable)
+}
+
+// expireOpen moves an open breaker to half-open once the open timeout
+// expired, cb.mu must be held
+func (cb *CircuitBreaker) expireOpen(ctx context.Context, now time.Time) {
+	if cb.state == CircuitOpen && now.Sub(cb.openedAt) >= cb.opts.OpenTimeout {
+		cb.transition(ctx, CircuitHalfOpen, now)
+	}
+}
+
+// bucket returns the bucket for now, resetting it if it belongs to a
+// previous turn of the window. cb.mu must be held.
+func (cb *CircuitBreaker) bucket(now time.Time) *breakerBucket {
+	width := max(cb.opts.Window/time.Duration(len(cb.buckets)), time.Nanosecond)
+	start := now.Truncate(width)
+	b := &cb.buckets[int(start.UnixNano()/int64(width))%len(cb.buckets)]
+	if !b.start.Equal(start) {
+		*b = breakerBucket{start: start}
+	}
+	return b
+}
+
+// counts returns the number of calls and failures within the window,
+// cb.mu must be held
+func (cb *CircuitBreaker) counts(now time.Time) (total, failures int) {
+	cutoff := now.Add(-cb.opts.Window)
+	for i := range cb.buckets {
+		if cb.buckets[i].start.After(cutoff) {
+			total += cb.buckets[i].total
+			failures += cb.buckets[i].failures
+		}
+	}
+	return total, failures
+}
+
+// transition moves the breaker to a new state, cb.mu must be held
+func (cb *CircuitBreaker) transition(ctx context.Context, to CircuitState, now time.Time) {
+	from := cb.state
+	cb.state = to
+	cb.generation++
+	cb.probes = 0
+	cb.successes = 0
+	switch to {
+	case CircuitOpen:
+		cb.openedAt = now
+	case CircuitClosed:
+		clear(cb.buckets)
+	case CircuitHalfOpen:
+	}
+
+	breakerState.WithLabelValues(cb.Name).Set(float64(to))
+	breakerTransitions.WithLabelValues(cb.Name, from.String(), to.String()).Inc()
+
+	info := log.F{
+		"breaker": cb.Name,
+		"from":    from.String(),
+		"to":      to.String(),
+	}
+	if to == CircuitOpen {
+		log.Warn(ctx, "async.breaker state changed", info)
+	} else {
+		log.Info(ctx, "async.breaker state changed", info)
+	}
+}
+
+// breakerRunner implements the Runner returned by CircuitBreaker.Wrap
+type breakerRunner struct {
+	Runner
+	cb *CircuitBreaker
+}
+
+// Run implements the Runner interface
+func (b *breakerRunner) Run(ctx context.Context) error {
+	return b.cb.Execute(ctx, b.Runner.Run)
+}
+
+// Close implements the Closer interface by closing the wrapped runner
+func (b *breakerRunner) Close(ctx context.Context) error {
+	return RunClose(ctx, b.Runner)
+}
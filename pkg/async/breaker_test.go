@@ -0,0 +1,158 @@
+//go:build !or_e2e
+
+package async_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/getoutreach/gobox/pkg/async"
+	"github.com/getoutreach/gobox/pkg/orerr"
+	"github.com/getoutreach/gobox/pkg/statuscodes"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/assert/cmp"
+)
+
+func failWith(err error) func(context.Context) error {
+	return func(context.Context) error {
+		return err
+	}
+}
+
+func TestCircuitBreakerOpens(t *testing.T) {
+	cb := async.NewCircuitBreaker("test-opens",
+		async.WithFailureRatio(0.5, 4, time.Minute),
+		async.WithOpenTimeout(time.Hour, 1),
+	)
+
+	serverErr := errors.New("boom")
+	assert.NilError(t, cb.Execute(t.Context(), failWith(nil)))
+	assert.ErrorIs(t, cb.Execute(t.Context(), failWith(serverErr)), serverErr)
+	assert.ErrorIs(t, cb.Execute(t.Context(), failWith(serverErr)), serverErr)
+	assert.Equal(t, cb.State(), async.CircuitClosed, "below the minimum number of requests")
+
+	assert.ErrorIs(t, cb.Execute(t.Context(), failWith(serverErr)), serverErr)
+	assert.Equal(t, cb.State(), async.CircuitOpen)
+
+	calls := 0
+	err := cb.Execute(t.Context(), func(context.Context) error {
+		calls++
+		return nil
+	})
+	assert.ErrorIs(t, err, async.ErrCircuitOpen)
+	assert.Assert(t, orerr.IsErrorStatusCode(err, statuscodes.Unavailable))
+	assert.Equal(t, calls, 0)
+}
+
+func TestCircuitBreakerZeroWindow(t *testing.T) {
+	// a zero window is replaced by the default minute
+	cb := async.NewCircuitBreaker("test-zero-window", async.WithFailureRatio(0.5, 10, 0))
+
+	serverErr := errors.New("boom")
+	for range 10 {
+		assert.ErrorIs(t, cb.Execute(t.Context(), failWith(serverErr)), serverErr)
+	}
+	assert.Equal(t, cb.State(), async.CircuitOpen)
+}
+
+func TestCircuitBreakerIgnoresClientErrors(t *testing.T) {
+	cb := async.NewCircuitBreaker("test-client-errors", async.WithFailureRatio(0.01, 1, time.Minute))
+
+	clientErr := orerr.NewErrorStatus(errors.New("bad request"), statuscodes.BadRequest)
+	for range 10 {
+		assert.ErrorIs(t, cb.Execute(t.Context(), failWith(clientErr)), clientErr)
+		assert.ErrorIs(t, cb.Execute(t.Context(), failWith(context.Canceled)), context.Canceled)
+	}
+	assert.Equal(t, cb.State(), async.CircuitClosed)
+
+	assert.Assert(t, cb.Execute(t.Context(), failWith(orerr.NewErrorStatus(errors.New("down"), statuscodes.Unavailable))) != nil)
+	assert.Equal(t, cb.State(), async.CircuitOpen)
+}
+
+func TestCircuitBreakerHalfOpen(t *testing.T) {
+	cb := async.NewCircuitBreaker("test-half-open",
+		async.WithFailureRatio(0, 1, time.Minute),
+		async.WithOpenTimeout(10*time.Millisecond, 2),
+	)
+
+	assert.Assert(t, cb.Execute(t.Context(), failWith(errors.New("boom"))) != nil)
+	assert.Equal(t, cb.State(), async.CircuitOpen)
+
+	// a failed probe opens the breaker again
+	time.Sleep(15 * time.Millisecond)
+	assert.Equal(t, cb.State(), async.CircuitHalfOpen)
+	assert.Assert(t, cb.Execute(t.Context(), failWith(errors.New("still down"))) != nil)
+	assert.Equal(t, cb.State(), async.CircuitOpen)
+
+	// enough successful probes close it
+	time.Sleep(15 * time.Millisecond)
+	assert.NilError(t, cb.Execute(t.Context(), failWith(nil)))
+	assert.Equal(t, cb.State(), async.CircuitHalfOpen)
+	assert.NilError(t, cb.Execute(t.Context(), failWith(nil)))
+	assert.Equal(t, cb.State(), async.CircuitClosed)
+}
+
+func TestCircuitBreakerLimitsProbes(t *testing.T) {
+	cb := async.NewCircuitBreaker("test-probes",
+		async.WithFailureRatio(0, 1, time.Minute),
+		async.WithOpenTimeout(time.Millisecond, 1),
+	)
+	assert.Assert(t, cb.Execute(t.Context(), failWith(errors.New("boom"))) != nil)
+	time.Sleep(5 * time.Millisecond)
+
+	probing := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- cb.Execute(t.Context(), func(context.Context) error {
+			close(probing)
+			<-release
+			return nil
+		})
+	}()
+
+	<-probing
+	assert.ErrorIs(t, cb.Execute(t.Context(), failWith(nil)), async.ErrCircuitOpen)
+	close(release)
+	assert.NilError(t, <-done)
+	assert.Equal(t, cb.State(), async.CircuitClosed)
+}
+
+func TestCircuitBreakerPanicReleasesProbe(t *testing.T) {
+	cb := async.NewCircuitBreaker("test-panic",
+		async.WithFailureRatio(0, 1, time.Minute),
+		async.WithOpenTimeout(10*time.Millisecond, 1),
+	)
+	assert.Assert(t, cb.Execute(t.Context(), failWith(errors.New("boom"))) != nil)
+	time.Sleep(15 * time.Millisecond)
+	assert.Equal(t, cb.State(), async.CircuitHalfOpen)
+
+	// a panicking probe is a failed probe
+	assert.Assert(t, cmp.Panics(func() {
+		//nolint:errcheck // Why: Execute panics
+		cb.Execute(t.Context(), func(context.Context) error {
+			panic("probe")
+		})
+	}))
+	assert.Equal(t, cb.State(), async.CircuitOpen)
+
+	time.Sleep(15 * time.Millisecond)
+	assert.NilError(t, cb.Execute(t.Context(), failWith(nil)))
+	assert.Equal(t, cb.State(), async.CircuitClosed)
+}
+
+func TestCircuitBreakerWrap(t *testing.T) {
+	cb := async.NewCircuitBreaker("test-wrap", async.WithFailureRatio(0, 1, time.Minute))
+	r := cb.Wrap(async.Func(failWith(errors.New("boom"))))
+
+	assert.ErrorContains(t, r.Run(t.Context()), "boom")
+	assert.ErrorIs(t, r.Run(t.Context()), async.ErrCircuitOpen)
+
+	v, err := async.ExecuteValue(t.Context(), cb, func(context.Context) (int, error) {
+		return 1, nil
+	})
+	assert.ErrorIs(t, err, async.ErrCircuitOpen)
+	assert.Equal(t, v, 0)
+}
@@ -0,0 +1,255 @@
+// Copyright 2026 Outreach Corporation. All Rights Reserved.
+
+// Description: Provides interval and cron schedules
+
+package async
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule computes when a scheduled job should run next.
+type Schedule interface {
+	// Next returns the next activation time strictly after t, in t's
+	// location. A zero time means there is no next activation.
+	Next(t time.Time) time.Time
+}
+
+// Every returns a Schedule which activates at fixed intervals of d.
+//
+// The next activation is computed from the previous activation rather
+// than from when the job finished, so runs do not drift.
+//
+// As with time.NewTicker, d must be greater than zero; if not, Every
+// will panic. Use ParseCron to get an error instead.
+func Every(d time.Duration) Schedule {
+	if d <= 0 {
+		panic("async: non-positive interval for Every")
+	}
+	return intervalSchedule(d)
+}
+
+// intervalSchedule implements Every
+type intervalSchedule time.Duration
+
+// Next implements the Schedule interface
+func (s intervalSchedule) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(s))
+}
+
+// cronSchedule is a parsed cron expression. Each field is a bit set of
+// the values it matches.
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+
+	// domStar and dowStar record whether day of month or day of week
+	// were unrestricted ("*"), which changes how they are combined.
+	domStar, dowStar bool
+}
+
+// cronDescriptors are the supported shorthands for common expressions
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// cronField describes the bounds and names of a cron field
+type cronField struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	cronMinute = cronField{name: "minute", min: 0, max: 59}
+	cronHour   = cronField{name: "hour", min: 0, max: 23}
+	cronDom    = cronField{name: "day of month", min: 1, max: 31}
+	cronMonth  = cronField{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// day of week accepts 7 as an alias for Sunday
+	cronDow = cronField{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+// ParseCron parses a standard five field cron expression
+// ("minute hour day-of-month month day-of-week") into a Schedule.
+//
+// Fields support "*", lists ("1,15"), ranges ("1-5"), steps ("*/10",
+// "0-30/5") and three letter month and weekday names. The descriptors
+// @yearly, @annually, @monthly, @weekly, @daily, @midnight and @hourly
+// are supported too, as is "@every <duration>".
+//
+// Activation times are computed in the location of the time passed to
+// Next, see WithLocation.
+//
+//	// every day at 02:00
+//	s, err := async.ParseCron("0 2 * * *")
+func ParseCron(expr string) (Schedule, error) {
+	expr = strings.TrimSpace(expr)
+	if rest, ok := strings.CutPrefix(expr, "@every "); ok {
+		d, err := time.ParseDuration(strings.TrimSpace(rest))
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron expression %q: %w", expr, err)
+		}
+		if d <= 0 {
+			return nil, fmt.Errorf("invalid cron expression %q: duration must be positive", expr)
+		}
+		return Every(d), nil
+	}
+	if d, ok := cronDescriptors[expr]; ok {
+		expr = d
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid cron expression %q: expected 5 fields, got %d", expr, len(fields))
+	}
+
+	var s cronSchedule
+	var err error
+	if s.minute, err = cronMinute.parse(fields[0]); err != nil {
+		return nil, fmt.Errorf("invalid cron expression %q: %w", expr, err)
+	}
+	if s.hour, err = cronHour.parse(fields[1]); err != nil {
+		return nil, fmt.Errorf("invalid cron expression %q: %w", expr, err)
+	}
+	if s.dom, err = cronDom.parse(fields[2]); err != nil {
+		return nil, fmt.Errorf("invalid cron expression %q: %w", expr, err)
+	}
+	if s.month, err = cronMonth.parse(fields[3]); err != nil {
+		return nil, fmt.Errorf("invalid cron expression %q: %w", expr, err)
+	}
+	if s.dow, err = cronDow.parse(fields[4]); err != nil {
+		return nil, fmt.Errorf("invalid cron expression %q: %w", expr, err)
+	}
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	s.domStar = fields[2] == "*" || fields[2] == "?"
+	s.dowStar = fields[4] == "*" || fields[4] == "?"
+	return &s, nil
+}
+
+// MustParseCron is like ParseCron but panics if the expression is invalid.
+func MustParseCron(expr string) Schedule {
+	s, err := ParseCron(expr)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// parse parses a single cron field into a bit set
+func (f cronField) parse(field string) (uint64, error) {
+	var bits uint64
+	for _, item := range strings.Split(field, ",") {
+		rangeExpr, stepExpr, hasStep := strings.Cut(item, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepExpr); err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step %q in %s field", stepExpr, f.name)
+			}
+		}
+
+		lo, hi := f.min, f.max
+		switch {
+		case rangeExpr == "*" || rangeExpr == "?":
+		case strings.Contains(rangeExpr, "-"):
+			loExpr, hiExpr, _ := strings.Cut(rangeExpr, "-")
+			var err error
+			if lo, err = f.value(loExpr); err != nil {
+				return 0, err
+			}
+			if hi, err = f.value(hiExpr); err != nil {
+				return 0, err
+			}
+			if lo > hi {
+				return 0, fmt.Errorf("invalid range %q in %s field", rangeExpr, f.name)
+			}
+		default:
+			v, err := f.value(rangeExpr)
+			if err != nil {
+				return 0, err
+			}
+			lo = v
+			if !hasStep {
+				hi = v
+			}
+		}
+
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// value parses a single number or name of the field
+func (f cronField) value(s string) (int, error) {
+	if v, ok := f.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("invalid value %q in %s field, expected %d-%d", s, f.name, f.min, f.max)
+	}
+	return v, nil
+}
+
+// cronSearchYears bounds the search for the next activation, which
+// protects against expressions that can never match (e.g. "0 0 30 2 *").
+const cronSearchYears = 5
+
+// Next implements the Schedule interface
+func (s *cronSchedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	// start at the beginning of the next minute
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.Year() + cronSearchYears
+
+	for t.Year() <= limit {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			// adding (rather than using time.Date) keeps moving forward
+			// through repeated wall clock times when DST ends
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// dayMatches follows the cron convention: when both day of month and
+// day of week are restricted, matching either one is enough.
+func (s *cronSchedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
@@ -0,0 +1,70 @@
+//go:build !or_e2e
+
+package async_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/getoutreach/gobox/pkg/async"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/assert/cmp"
+)
+
+func TestParseCronNext(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	assert.NilError(t, err)
+
+	from := time.Date(2026, time.March, 14, 10, 30, 15, 0, time.UTC) // a Saturday
+	tests := []struct {
+		expr     string
+		from     time.Time
+		expected time.Time
+	}{
+		{"* * * * *", from, time.Date(2026, time.March, 14, 10, 31, 0, 0, time.UTC)},
+		{"0 2 * * *", from, time.Date(2026, time.March, 15, 2, 0, 0, 0, time.UTC)},
+		{"*/15 * * * *", from, time.Date(2026, time.March, 14, 10, 45, 0, 0, time.UTC)},
+		{"0 9 * * mon-fri", from, time.Date(2026, time.March, 16, 9, 0, 0, 0, time.UTC)},
+		{"0 0 1 jan *", from, time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", from, time.Date(2026, time.March, 15, 0, 0, 0, 0, time.UTC)},
+		{"0 0 13 * 1", from, time.Date(2026, time.March, 16, 0, 0, 0, 0, time.UTC)},
+		{"30 10,22 * * *", from, time.Date(2026, time.March, 14, 22, 30, 0, 0, time.UTC)},
+		{"@hourly", from, time.Date(2026, time.March, 14, 11, 0, 0, 0, time.UTC)},
+		{"@monthly", from, time.Date(2026, time.April, 1, 0, 0, 0, 0, time.UTC)},
+		{"@every 90s", from, from.Add(90 * time.Second)},
+		{"0 2 * * *", from.In(ny), time.Date(2026, time.March, 15, 2, 0, 0, 0, ny)},
+		{"0 0 30 2 *", from, time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			s, err := async.ParseCron(tt.expr)
+			assert.NilError(t, err)
+			assert.Assert(t, s.Next(tt.from).Equal(tt.expected), "got %s", s.Next(tt.from))
+		})
+	}
+}
+
+func TestParseCronErrors(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"@every nope",
+		"@every -1s",
+		"@every 0s",
+	} {
+		_, err := async.ParseCron(expr)
+		assert.Assert(t, err != nil, expr)
+	}
+}
+
+func TestEveryNonPositive(t *testing.T) {
+	assert.Assert(t, cmp.Panics(func() { async.Every(0) }))
+	assert.Assert(t, cmp.Panics(func() { async.Every(-time.Second) }))
+}
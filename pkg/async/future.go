@@ -0,0 +1,252 @@
+// Copyright 2026 Outreach Corporation. All Rights Reserved.
+
+// Description: Provides typed futures and fan-out helpers
+
+package async
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"sync"
+
+	"github.com/getoutreach/gobox/pkg/events"
+	"golang.org/x/sync/semaphore"
+)
+
+// Future is the eventual result of a function running asynchronously.
+type Future[T any] struct {
+	done chan struct{}
+	val  T
+	err  error
+}
+
+// NewFuture runs fn asynchronously via Default (like Run) and returns
+// a Future for its result.
+//
+//	user := async.NewFuture(ctx, func(ctx context.Context) (*User, error) {
+//	    return fetchUser(ctx, id)
+//	})
+//	...
+//	u, err := user.Await(ctx)
+func NewFuture[T any](ctx context.Context, fn func(ctx context.Context) (T, error)) *Future[T] {
+	return NewFutureOn(ctx, Default, fn)
+}
+
+// NewFutureOn runs fn asynchronously via t.Run and returns a Future for
+// its result. As with any task, the span and error logging are handled
+// by t.
+//
+// If fn panics, the Future resolves with a *PanicError and the panic
+// is propagated, so it is only recovered if t.RecoverPanics is set.
+func NewFutureOn[T any](ctx context.Context, t *Tasks, fn func(ctx context.Context) (T, error)) *Future[T] {
+	f := &Future[T]{done: make(chan struct{})}
+	t.Run(ctx, Func(func(ctx context.Context) error {
+		defer func() {
+			if r := recover(); r != nil {
+				var zero T
+				f.resolve(zero, &PanicError{Info: events.NewErrorInfoFromPanic(r)})
+				panic(r)
+			}
+		}()
+
+		val, err := fn(ctx)
+		f.resolve(val, err)
+		return err
+	}))
+	return f
+}
+
+// Resolved returns a Future which is already resolved with val and err
+func Resolved[T any](val T, err error) *Future[T] {
+	f := &Future[T]{done: make(chan struct{})}
+	f.resolve(val, err)
+	return f
+}
+
+// resolve sets the result of the future
+func (f *Future[T]) resolve(val T, err error) {
+	f.val, f.err = val, err
+	close(f.done)
+}
+
+// Done returns a channel which is closed once the Future is resolved
+func (f *Future[T]) Done() <-chan struct{} {
+	return f.done
+}
+
+// Await waits for the Future to resolve and returns its result. If the
+// context is done first, the context error is returned. The Future
+// itself keeps running and can be awaited again.
+func (f *Future[T]) Await(ctx context.Context) (T, error) {
+	select {
+	case <-f.done:
+		return f.val, f.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// settled yields the index of every future as it resolves, in the
+// order they resolve. It stops when ctx is done, returning its error.
+func settled[T any](ctx context.Context, futures []*Future[T], yield func(idx int) bool) error {
+	ch := make(chan int, len(futures))
+	stop := make(chan struct{})
+	defer close(stop)
+	for i, f := range futures {
+		go func() {
+			select {
+			case <-f.done:
+				ch <- i
+			case <-stop:
+			}
+		}()
+	}
+
+	for range futures {
+		select {
+		case idx := <-ch:
+			if !yield(idx) {
+				return nil
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// All waits for all futures to resolve successfully and returns their
+// values, in the order of the futures. It returns as soon as any future
+// fails, with that error.
+func All[T any](ctx context.Context, futures ...*Future[T]) ([]T, error) {
+	var firstErr error
+	err := settled(ctx, futures, func(idx int) bool {
+		firstErr = futures[idx].err
+		return firstErr == nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	result := make([]T, len(futures))
+	for i, f := range futures {
+		result[i] = f.val
+	}
+	return result, nil
+}
+
+// Any returns the value of the first future to resolve successfully.
+// If all futures fail, their errors are returned joined with errors.Join.
+func Any[T any](ctx context.Context, futures ...*Future[T]) (T, error) {
+	var (
+		result T
+		found  bool
+		errs   []error
+	)
+	err := settled(ctx, futures, func(idx int) bool {
+		if futures[idx].err != nil {
+			errs = append(errs, futures[idx].err)
+			return true
+		}
+		result, found = futures[idx].val, true
+		return false
+	})
+	switch {
+	case err != nil:
+		return result, err
+	case found:
+		return result, nil
+	case len(errs) == 0:
+		return result, errors.New("async.Any: no futures provided")
+	default:
+		return result, errors.Join(errs...)
+	}
+}
+
+// Race returns the result of the first future to resolve, whether it
+// succeeded or not.
+func Race[T any](ctx context.Context, futures ...*Future[T]) (T, error) {
+	if len(futures) == 0 {
+		var zero T
+		return zero, errors.New("async.Race: no futures provided")
+	}
+
+	winner := -1
+	if err := settled(ctx, futures, func(idx int) bool {
+		winner = idx
+		return false
+	}); err != nil {
+		var zero T
+		return zero, err
+	}
+	return futures[winner].val, futures[winner].err
+}
+
+// Map calls fn for every item of seq, running at most limit calls
+// concurrently (no limit if limit <= 0), and returns the results in the
+// order of seq.
+//
+// The calls run as tasks of a Tasks named "async.map", so they get
+// spans and error logging like Run. The first error cancels the context
+// of the remaining calls, stops consuming seq and is returned.
+//
+//	users, err := async.Map(ctx, slices.Values(ids), 10, fetchUser)
+func Map[T, R any](ctx context.Context, seq iter.Seq[T], limit int, fn func(ctx context.Context, item T) (R, error)) ([]R, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		once     sync.Once
+		firstErr error
+		sem      *semaphore.Weighted
+		futures  []*Future[R]
+	)
+	if limit > 0 {
+		sem = semaphore.NewWeighted(int64(limit))
+	}
+
+	tasks := NewTasks("async.map")
+	for item := range seq {
+		if sem != nil {
+			if err := sem.Acquire(ctx, 1); err != nil {
+				break
+			}
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		futures = append(futures, NewFutureOn(ctx, tasks, func(ctx context.Context) (R, error) {
+			if sem != nil {
+				defer sem.Release(1)
+			}
+			r, err := fn(ctx, item)
+			if err != nil {
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+			}
+			return r, err
+		}))
+	}
+	tasks.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	result := make([]R, len(futures))
+	for i, f := range futures {
+		result[i] = f.val
+	}
+	return result, nil
+}
@@ -0,0 +1,138 @@
+//go:build !or_e2e
+
+package async_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/getoutreach/gobox/pkg/async"
+	"gotest.tools/v3/assert"
+)
+
+// delayed returns a future resolving with val and err after d
+func delayed[T any](ctx context.Context, d time.Duration, val T, err error) *async.Future[T] {
+	return async.NewFuture(ctx, func(ctx context.Context) (T, error) {
+		async.Sleep(ctx, d)
+		return val, err
+	})
+}
+
+func TestFutureAwait(t *testing.T) {
+	f := delayed(t.Context(), time.Millisecond, 42, nil)
+	v, err := f.Await(t.Context())
+	assert.NilError(t, err)
+	assert.Equal(t, v, 42)
+
+	// awaiting again returns the same result
+	v, err = f.Await(t.Context())
+	assert.NilError(t, err)
+	assert.Equal(t, v, 42)
+}
+
+func TestFutureAwaitContext(t *testing.T) {
+	f := delayed(t.Context(), time.Hour, 42, nil)
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := f.Await(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestFuturePanic(t *testing.T) {
+	tasks := async.Tasks{Name: "future", RecoverPanics: true}
+	f := async.NewFutureOn(t.Context(), &tasks, func(context.Context) (int, error) {
+		panic("boom")
+	})
+
+	_, err := f.Await(t.Context())
+	var panicErr *async.PanicError
+	assert.Assert(t, errors.As(err, &panicErr))
+	tasks.Wait()
+}
+
+func TestFutureAll(t *testing.T) {
+	vals, err := async.All(t.Context(),
+		delayed(t.Context(), 10*time.Millisecond, 1, nil),
+		async.Resolved(2, nil),
+		delayed(t.Context(), time.Millisecond, 3, nil),
+	)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, vals, []int{1, 2, 3})
+
+	start := time.Now()
+	_, err = async.All(t.Context(),
+		delayed(t.Context(), time.Hour, 1, nil),
+		delayed(t.Context(), time.Millisecond, 0, errors.New("failed")),
+	)
+	assert.ErrorContains(t, err, "failed")
+	assert.Assert(t, time.Since(start) < time.Minute, "All must fail fast")
+}
+
+func TestFutureAny(t *testing.T) {
+	v, err := async.Any(t.Context(),
+		async.Resolved(0, errors.New("first")),
+		delayed(t.Context(), 5*time.Millisecond, 2, nil),
+		delayed(t.Context(), time.Hour, 3, nil),
+	)
+	assert.NilError(t, err)
+	assert.Equal(t, v, 2)
+
+	_, err = async.Any(t.Context(),
+		async.Resolved(0, errors.New("first")),
+		async.Resolved(0, errors.New("second")),
+	)
+	assert.ErrorContains(t, err, "first")
+	assert.ErrorContains(t, err, "second")
+}
+
+func TestFutureRace(t *testing.T) {
+	_, err := async.Race(t.Context(),
+		delayed(t.Context(), time.Hour, 1, nil),
+		delayed(t.Context(), time.Millisecond, 0, errors.New("fast failure")),
+	)
+	assert.ErrorContains(t, err, "fast failure")
+
+	_, err = async.Race[int](t.Context())
+	assert.Assert(t, err != nil)
+}
+
+func TestFutureMap(t *testing.T) {
+	var active, maxActive atomic.Int32
+	results, err := async.Map(t.Context(), slices.Values([]int{1, 2, 3, 4, 5, 6}), 2,
+		func(_ context.Context, item int) (int, error) {
+			n := active.Add(1)
+			defer active.Add(-1)
+			for {
+				m := maxActive.Load()
+				if n <= m || maxActive.CompareAndSwap(m, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			return item * item, nil
+		})
+
+	assert.NilError(t, err)
+	assert.DeepEqual(t, results, []int{1, 4, 9, 16, 25, 36})
+	assert.Assert(t, maxActive.Load() <= 2)
+}
+
+func TestFutureMapError(t *testing.T) {
+	var calls atomic.Int32
+	_, err := async.Map(t.Context(), slices.Values([]int{1, 2, 3, 4, 5, 6}), 1,
+		func(_ context.Context, item int) (int, error) {
+			calls.Add(1)
+			if item == 2 {
+				return 0, errors.New("failed")
+			}
+			return item, nil
+		})
+
+	assert.ErrorContains(t, err, "failed")
+	assert.Equal(t, calls.Load(), int32(2))
+}
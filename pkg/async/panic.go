@@ -0,0 +1,51 @@
+// Copyright 2026 Outreach Corporation. All Rights Reserved.
+
+// Description: Provides panic recovery for runners
+
+package async
+
+import (
+	"context"
+
+	"github.com/getoutreach/gobox/pkg/events"
+	"github.com/getoutreach/gobox/pkg/trace"
+)
+
+// PanicError is the error produced when a panic is recovered from a
+// Runner. Info holds the result of events.NewErrorInfoFromPanic,
+// including the stack at the point of the panic.
+type PanicError struct {
+	Info *events.ErrorInfo
+}
+
+// Error implements the err interface.
+func (e *PanicError) Error() string {
+	return "panic: " + e.Info.Error
+}
+
+// Unwrap returns the inner error. If the panic value was an error,
+// this is that error.
+func (e *PanicError) Unwrap() error {
+	return e.Info.RawError
+}
+
+// MarshalLog implements the log.Marshaler interface
+func (e *PanicError) MarshalLog(addField func(field string, value interface{})) {
+	e.Info.MarshalLog(addField)
+	addField("error.kind", "panic")
+}
+
+// Recover wraps r so that a panic inside Run is recovered and returned
+// as a *PanicError. The panic is also recorded on the current trace span.
+func Recover(r Runner) Runner {
+	return Func(func(ctx context.Context) (err error) {
+		defer func() {
+			if p := recover(); p != nil {
+				err = &PanicError{Info: events.NewErrorInfoFromPanic(p)}
+				//nolint:errcheck // Why: Error returns its input
+				trace.Error(ctx, err)
+			}
+		}()
+		return r.Run(ctx)
+	})
+}
@@ -0,0 +1,80 @@
+//go:build !or_e2e
+
+package async_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/getoutreach/gobox/pkg/async"
+	"gotest.tools/v3/assert"
+)
+
+func TestRecover(t *testing.T) {
+	err := async.Recover(async.Func(func(context.Context) error {
+		panic("boom")
+	})).Run(t.Context())
+
+	var panicErr *async.PanicError
+	assert.Assert(t, errors.As(err, &panicErr))
+	assert.Equal(t, panicErr.Info.Kind, "panic")
+	assert.Error(t, err, "panic: boom")
+	assert.Assert(t, len(panicErr.Info.Stack) > 0)
+}
+
+func TestRecoverErrorValue(t *testing.T) {
+	sentinel := errors.New("sentinel")
+	err := async.Recover(async.Func(func(context.Context) error {
+		panic(sentinel)
+	})).Run(t.Context())
+
+	assert.ErrorIs(t, err, sentinel)
+}
+
+func TestTasksCollectErrors(t *testing.T) {
+	tasks := async.Tasks{Name: "collect", RecoverPanics: true, CollectErrors: true}
+	tasks.Run(t.Context(), async.Func(func(context.Context) error {
+		panic("boom")
+	}))
+	tasks.Run(t.Context(), async.Func(func(context.Context) error {
+		return errors.New("failed")
+	}))
+	tasks.Run(t.Context(), async.Func(func(context.Context) error {
+		return context.Canceled
+	}))
+	tasks.Run(t.Context(), async.Func(func(context.Context) error {
+		return nil
+	}))
+
+	err := tasks.WaitErr()
+	assert.ErrorContains(t, err, "panic: boom")
+	assert.ErrorContains(t, err, "failed")
+	assert.Assert(t, !errors.Is(err, context.Canceled))
+}
+
+func TestTasksWaitErrWithoutCollecting(t *testing.T) {
+	tasks := async.Tasks{Name: "no-collect"}
+	tasks.Run(t.Context(), async.Func(func(context.Context) error {
+		return errors.New("failed")
+	}))
+	assert.NilError(t, tasks.WaitErr())
+}
+
+func TestRunGroupCollect(t *testing.T) {
+	closer := &runWithCloser{}
+	err := async.RunGroupCollect([]async.Runner{
+		async.Func(func(context.Context) error {
+			panic("boom")
+		}),
+		async.Func(func(context.Context) error {
+			return errors.New("failed")
+		}),
+		closer,
+	}).Run(t.Context())
+
+	assert.ErrorContains(t, err, "panic: boom")
+	assert.ErrorContains(t, err, "failed")
+	assert.Assert(t, !errors.Is(err, context.Canceled))
+	assert.Assert(t, closer.isclosed)
+}
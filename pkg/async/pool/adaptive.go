@@ -0,0 +1,235 @@
+// Copyright 2026 Outreach Corporation. All Rights Reserved.
+
+// Description: Provides adaptive sizing for the async pool
+
+package pool
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/getoutreach/gobox/pkg/log"
+)
+
+// AdaptiveOptions configures an AdaptiveSize
+type AdaptiveOptions struct {
+	// TargetWait is the queue wait time above which the pool grows
+	TargetWait time.Duration
+
+	// LatencyTolerance is how many times slower than usual tasks may get
+	// before the pool shrinks, as running more of them concurrently is
+	// then likely overloading a dependency
+	LatencyTolerance float64
+
+	// Increase is the number of workers added when tasks wait too long
+	Increase int
+
+	// Decrease is the factor the number of workers is multiplied by
+	// when tasks get too slow
+	Decrease float64
+}
+
+// AdaptiveOption configures an AdaptiveSize
+type AdaptiveOption func(*AdaptiveOptions)
+
+// WithTargetWait sets the queue wait time above which the pool grows
+func WithTargetWait(d time.Duration) AdaptiveOption {
+	return func(opts *AdaptiveOptions) {
+		opts.TargetWait = d
+	}
+}
+
+// WithLatencyTolerance sets how many times slower than usual tasks may
+// get before the pool shrinks
+func WithLatencyTolerance(tolerance float64) AdaptiveOption {
+	return func(opts *AdaptiveOptions) {
+		opts.LatencyTolerance = tolerance
+	}
+}
+
+// WithAIMD sets the number of workers added when tasks wait too long
+// and the factor the number of workers is multiplied by when tasks get
+// too slow. An increase below 1 is raised to 1 and a decrease outside of
+// (0, 1) is replaced by the default 0.75, so that the pool always grows
+// when tasks wait and shrinks when they get slow.
+func WithAIMD(increase int, decrease float64) AdaptiveOption {
+	return func(opts *AdaptiveOptions) {
+		opts.Increase = increase
+		opts.Decrease = decrease
+	}
+}
+
+// AdaptiveSize sizes a pool from the time tasks wait in the queue and
+// the time they take to run, within min and max workers.
+//
+// Every ResizeEvery, it looks at the tasks which started since the
+// previous resize and follows an AIMD (additive increase, multiplicative
+// decrease) policy:
+//
+//   - tasks got slower than LatencyTolerance times their usual latency:
+//     the workers are likely overloading a dependency, the size is
+//     multiplied by Decrease
+//   - tasks waited longer than TargetWait in the queue on average: the
+//     size grows by Increase
+//   - the workers were busy less than half of the time: the size
+//     shrinks by one
+//
+// When no task completed since the previous resize, but every worker is
+// busy and tasks are queued, long running tasks are saturating the pool
+// and the size grows by Increase too.
+//
+// The decisions are logged by the pool with its "async.pool resized"
+// entry, along with the reason, the average wait and latency.
+//
+// An AdaptiveSize follows the tasks of a single pool: passing it to a
+// second pool panics, create one per pool instead.
+//
+//	p := pool.New(ctx,
+//	    pool.NewAdaptiveSize(2, 64, pool.WithTargetWait(50*time.Millisecond)),
+//	    pool.ResizeEvery(5*time.Second),
+//	)
+type AdaptiveSize struct {
+	opts     *AdaptiveOptions
+	min, max int
+
+	mu       sync.Mutex
+	size     int
+	last     time.Time
+	samples  int
+	waited   time.Duration
+	busy     time.Duration
+	baseline time.Duration
+	decision log.F
+
+	// stats are the counters of the pool, to see the tasks which are
+	// queued or running but did not complete yet
+	stats *poolStats
+}
+
+// NewAdaptiveSize creates an AdaptiveSize starting with min workers.
+// It is an Option setting the size of the pool.
+func NewAdaptiveSize(minSize, maxSize int, options ...AdaptiveOption) *AdaptiveSize {
+	opts := &AdaptiveOptions{
+		TargetWait:       100 * time.Millisecond,
+		LatencyTolerance: 2,
+		Increase:         1,
+		Decrease:         0.75,
+	}
+	for _, o := range options {
+		o(opts)
+	}
+	opts.Increase = max(opts.Increase, 1)
+	if opts.Decrease <= 0 || opts.Decrease >= 1 {
+		opts.Decrease = 0.75
+	}
+	minSize = max(minSize, 1)
+	return &AdaptiveSize{opts: opts, min: minSize, max: max(maxSize, minSize), size: minSize, last: time.Now()}
+}
+
+// Apply implementation of Option interface
+func (a *AdaptiveSize) Apply(opts *Options) {
+	opts.Size = a.Size
+	opts.adaptive = a
+}
+
+// attach makes the AdaptiveSize follow the pool with the given stats.
+// It panics if the AdaptiveSize already follows another pool, as the
+// tasks of both would be mixed.
+func (a *AdaptiveSize) attach(stats *poolStats) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.stats != nil && a.stats != stats {
+		panic("pool: AdaptiveSize shared between pools")
+	}
+	a.stats = stats
+}
+
+// observe records a task which waited in the queue and ran for the
+// given durations
+func (a *AdaptiveSize) observe(waited, latency time.Duration) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.samples++
+	a.waited += waited
+	a.busy += latency
+}
+
+// Size is the SizeFunc of the pool, it computes the new size from the
+// tasks observed since the previous call
+func (a *AdaptiveSize) Size() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := time.Now()
+	elapsed := now.Sub(a.last)
+	if a.samples == 0 || elapsed <= 0 {
+		a.last = now
+		a.decision = nil
+		if a.saturated() {
+			previous := a.size
+			a.size = min(a.size+a.opts.Increase, a.max)
+			if a.size != previous {
+				a.decision = log.F{"reason": "saturated"}
+			}
+		}
+		// otherwise nothing ran, keep the size: the pool may simply be
+		// idle
+		return a.size
+	}
+
+	wait := a.waited / time.Duration(a.samples)
+	latency := a.busy / time.Duration(a.samples)
+	utilization := float64(a.busy) / (float64(elapsed) * float64(a.size))
+
+	previous := a.size
+	reason := ""
+	switch {
+	case a.baseline > 0 && float64(latency) > a.opts.LatencyTolerance*float64(a.baseline):
+		reason = "latency"
+		a.size = int(math.Floor(float64(a.size) * a.opts.Decrease))
+	case wait > a.opts.TargetWait:
+		reason = "queue_wait"
+		a.size += a.opts.Increase
+	case utilization < 0.5:
+		reason = "idle"
+		a.size--
+	}
+	a.size = min(max(a.size, a.min), a.max)
+
+	// the baseline follows the usual latency slowly so that a sudden
+	// slowdown stands out
+	if a.baseline == 0 {
+		a.baseline = latency
+	} else {
+		a.baseline = (a.baseline*9 + latency) / 10
+	}
+
+	a.decision = nil
+	if a.size != previous {
+		a.decision = log.F{
+			"reason":             reason,
+			"queue_wait_seconds": wait.Seconds(),
+			"latency_seconds":    latency.Seconds(),
+			"utilization":        utilization,
+		}
+	}
+	a.last, a.samples, a.waited, a.busy = now, 0, 0, 0
+	return a.size
+}
+
+// saturated returns whether every worker is running a task while more
+// tasks are queued. a.mu must be held.
+func (a *AdaptiveSize) saturated() bool {
+	if a.stats == nil {
+		return false
+	}
+	return a.stats.queued.Load() > 0 && a.stats.running.Load() >= int64(a.size)
+}
+
+// lastDecision returns the details of the last resize, if any
+func (a *AdaptiveSize) lastDecision() log.F {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.decision
+}
@@ -0,0 +1,91 @@
+package pool_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/getoutreach/gobox/pkg/async"
+	"github.com/getoutreach/gobox/pkg/async/pool"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/assert/cmp"
+)
+
+func TestAdaptiveSizeGrowsWhenTasksWait(t *testing.T) {
+	size := pool.NewAdaptiveSize(1, 4, pool.WithTargetWait(time.Millisecond))
+	p := pool.New(t.Context(), size, pool.ResizeEvery(10*time.Millisecond), pool.BufferLength(100))
+	defer p.Close()
+	scheduler, wait := pool.WithWait(p)
+
+	var active, maxActive atomic.Int32
+	for range 60 {
+		assert.NilError(t, scheduler.Schedule(t.Context(), async.Func(func(context.Context) error {
+			n := active.Add(1)
+			defer active.Add(-1)
+			for {
+				m := maxActive.Load()
+				if n <= m || maxActive.CompareAndSwap(m, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			return nil
+		})))
+	}
+	wait()
+
+	assert.Assert(t, maxActive.Load() > 1, "the pool did not grow")
+	assert.Assert(t, maxActive.Load() <= 4, "the pool grew above its maximum")
+	assert.Assert(t, size.Size() <= 4)
+}
+
+func TestAdaptiveSizeIdle(t *testing.T) {
+	size := pool.NewAdaptiveSize(2, 8)
+	assert.Equal(t, size.Size(), 2, "starts at the minimum")
+	assert.Equal(t, size.Size(), 2, "keeps its size without tasks")
+}
+
+func TestAdaptiveSizeGrowsWhenSaturated(t *testing.T) {
+	assertGrowsWhenSaturated(t, pool.NewAdaptiveSize(1, 3))
+}
+
+func TestAdaptiveSizeInvalidAIMD(t *testing.T) {
+	// an increase of 0 is raised to 1, the pool still grows
+	assertGrowsWhenSaturated(t, pool.NewAdaptiveSize(1, 3, pool.WithAIMD(0, 2)))
+}
+
+func TestAdaptiveSizeSharedPanics(t *testing.T) {
+	size := pool.NewAdaptiveSize(1, 3)
+	p := pool.New(t.Context(), size)
+	defer p.Close()
+
+	assert.Assert(t, cmp.Panics(func() {
+		pool.New(t.Context(), size)
+	}))
+}
+
+func assertGrowsWhenSaturated(t *testing.T, size *pool.AdaptiveSize) {
+	t.Helper()
+	p := pool.New(t.Context(), size, pool.ResizeEvery(10*time.Millisecond), pool.BufferLength(10))
+	defer p.Close()
+
+	// none of the tasks completes until all of them are running
+	release := make(chan struct{})
+	var started atomic.Int32
+	for range 3 {
+		assert.NilError(t, p.Schedule(t.Context(), async.Func(func(context.Context) error {
+			if started.Add(1) == 3 {
+				close(release)
+			}
+			<-release
+			return nil
+		})))
+	}
+
+	select {
+	case <-release:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("the pool did not grow: %d tasks started", started.Load())
+	}
+}
@@ -0,0 +1,234 @@
+// Copyright 2026 Outreach Corporation. All Rights Reserved.
+
+// Description: Provides priority and fairness aware scheduling for the async pool
+
+package pool
+
+import (
+	"context"
+
+	"github.com/getoutreach/gobox/pkg/async"
+	"github.com/getoutreach/gobox/pkg/queue"
+)
+
+// fairScale is the virtual cost of running an item with a weight of 1.
+// Items of a key with weight w cost fairScale/w.
+const fairScale = 1 << 16
+
+// ScheduleOption configures how a single runner is scheduled by a pool
+// created with FairScheduling
+type ScheduleOption func(*scheduleOptions)
+
+type scheduleOptions struct {
+	priority int
+	key      string
+}
+
+// WithPriority sets the priority of the runner. Runners with a higher
+// priority always run before runners with a lower one. Defaults to 0.
+func WithPriority(priority int) ScheduleOption {
+	return func(opts *scheduleOptions) {
+		opts.priority = priority
+	}
+}
+
+// WithFairnessKey sets the key (e.g. the tenant) the runner is
+// accounted to. Runners of the same priority are shared between keys
+// according to their weight, see FairScheduling.
+func WithFairnessKey(key string) ScheduleOption {
+	return func(opts *scheduleOptions) {
+		opts.key = key
+	}
+}
+
+// scheduleOptionsKey is the context key of the schedule options
+type scheduleOptionsKey struct{}
+
+// WithScheduleOptions returns a context carrying the schedule options,
+// which are picked up by the pool when the context is passed to
+// Schedule. Since it goes through the context, it works with any
+// Scheduler wrapping the pool (WithTimeout, WithWait, WithLogging).
+func WithScheduleOptions(ctx context.Context, options ...ScheduleOption) context.Context {
+	opts := scheduleOptionsFrom(ctx)
+	for _, o := range options {
+		o(&opts)
+	}
+	return context.WithValue(ctx, scheduleOptionsKey{}, opts)
+}
+
+// scheduleOptionsFrom returns the schedule options carried by ctx
+func scheduleOptionsFrom(ctx context.Context) scheduleOptions {
+	opts, _ := ctx.Value(scheduleOptionsKey{}).(scheduleOptions)
+	return opts
+}
+
+// ScheduleWith schedules r with the given options.
+//
+//	pool.ScheduleWith(ctx, scheduler, runner,
+//	    pool.WithPriority(1),
+//	    pool.WithFairnessKey(tenantID),
+//	)
+func ScheduleWith(ctx context.Context, s Scheduler, r async.Runner, options ...ScheduleOption) error {
+	return s.Schedule(WithScheduleOptions(ctx, options...), r)
+}
+
+// FairScheduling makes the pool run the queued runners by priority and
+// fairness key instead of in FIFO order.
+//
+// Priorities are strict: a runner only runs when no runner with a
+// higher priority is queued. Within a priority, runners are picked with
+// weighted fair queuing across their fairness keys, so that a key with
+// many queued runners cannot starve the others. weights gives the share
+// of each key, keys which are not listed have a weight of 1.
+//
+// Runners are reordered once they are in the pool queue: up to
+// BufferLength runners are reordered, and the ScheduleBehavior applies
+// when they are full.
+func FairScheduling(weights map[string]int) OptionFunc {
+	return func(opts *Options) {
+		opts.FairScheduling = true
+		opts.FairnessWeights = weights
+	}
+}
+
+// fairKey identifies a fairness key within a priority level
+type fairKey struct {
+	priority int
+	key      string
+}
+
+// fairItem is a queued unit along with its virtual start time
+type fairItem struct {
+	unit
+	key   fairKey
+	start int64
+}
+
+// fairQueue orders units by strict priority and, within a priority, by
+// start-time fair queuing across fairness keys. It is only used by the
+// dispatcher goroutine and is not safe for concurrent use.
+type fairQueue struct {
+	weights map[string]int
+
+	// levels holds a heap of items per priority, ordered by virtual
+	// finish time
+	levels map[int]*queue.PriorityQueue
+
+	// virtual is the virtual time of every priority, the start time of
+	// the last dispatched item
+	virtual map[int]int64
+
+	// finish is the virtual finish time of the last item queued per key
+	// and queued the number of queued items per key
+	finish map[fairKey]int64
+	queued map[fairKey]int
+
+	len int
+}
+
+func newFairQueue(weights map[string]int) *fairQueue {
+	return &fairQueue{
+		weights: weights,
+		levels:  map[int]*queue.PriorityQueue{},
+		virtual: map[int]int64{},
+		finish:  map[fairKey]int64{},
+		queued:  map[fairKey]int{},
+	}
+}
+
+// push queues a unit according to its schedule options
+func (q *fairQueue) push(u unit) {
+	opts := scheduleOptionsFrom(u.Context)
+	key := fairKey{priority: opts.priority, key: opts.key}
+
+	weight := int64(1)
+	if w := q.weights[opts.key]; w > 0 {
+		weight = int64(w)
+	}
+	// an idle key starts at the current virtual time, a backlogged key
+	// after its last queued item
+	start := max(q.virtual[key.priority], q.finish[key])
+	finish := start + fairScale/weight
+	q.finish[key] = finish
+	q.queued[key]++
+
+	level, ok := q.levels[key.priority]
+	if !ok {
+		level = queue.NewPriorityQueue()
+		q.levels[key.priority] = level
+	}
+	//nolint:errcheck // Why: the queue has no capacity limit
+	level.Push(&fairItem{unit: u, key: key, start: start}, finish)
+	q.len++
+}
+
+// peek returns the next unit without removing it
+func (q *fairQueue) peek() unit {
+	return q.top().GetData().(*fairItem).unit
+}
+
+// pop removes the next unit
+func (q *fairQueue) pop() {
+	item := q.levels[q.topPriority()].Pop().GetData().(*fairItem)
+	q.len--
+	q.virtual[item.key.priority] = item.start
+
+	if q.queued[item.key]--; q.queued[item.key] == 0 {
+		delete(q.queued, item.key)
+		delete(q.finish, item.key)
+	}
+	if q.levels[item.key.priority].Len() == 0 {
+		delete(q.levels, item.key.priority)
+		delete(q.virtual, item.key.priority)
+	}
+}
+
+// top returns the first item of the highest priority
+func (q *fairQueue) top() *queue.PriorityQueueItem {
+	return q.levels[q.topPriority()].Peek()
+}
+
+// topPriority returns the highest priority with queued items
+func (q *fairQueue) topPriority() int {
+	first := true
+	var highest int
+	for priority := range q.levels {
+		if first || priority > highest {
+			highest, first = priority, false
+		}
+	}
+	return highest
+}
+
+// dispatch moves the runners scheduled on p.intake to the workers in
+// the order of the fair queue
+func (p *Pool) dispatch(ctx context.Context) {
+	defer p.wg.Done()
+
+	fq := newFairQueue(p.opts.FairnessWeights)
+	capacity := max(p.opts.BufferLength, 1)
+	for {
+		intake := p.intake
+		if fq.len >= capacity {
+			intake = nil
+		}
+		var (
+			ready chan unit
+			next  unit
+		)
+		if fq.len > 0 {
+			ready, next = p.queue, fq.peek()
+		}
+
+		select {
+		case u := <-intake:
+			fq.push(u)
+		case ready <- next:
+			fq.pop()
+		case <-p.closed:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
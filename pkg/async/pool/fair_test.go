@@ -0,0 +1,77 @@
+package pool_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/getoutreach/gobox/pkg/async"
+	"github.com/getoutreach/gobox/pkg/async/pool"
+	"gotest.tools/v3/assert"
+)
+
+// fairRun schedules one runner per item on a single worker
+// fair pool, once the worker is busy, and returns the order they ran in
+func fairRun(t *testing.T, weights map[string]int, items []string, options func(item string) []pool.ScheduleOption) []string {
+	p := pool.New(t.Context(), pool.ConstantSize(1), pool.BufferLength(100), pool.FairScheduling(weights))
+	defer p.Close()
+	scheduler, wait := pool.WithWait(p)
+
+	release := make(chan struct{})
+	assert.NilError(t, scheduler.Schedule(t.Context(), async.Func(func(context.Context) error {
+		<-release
+		return nil
+	})))
+
+	var (
+		mu    sync.Mutex
+		order []string
+	)
+	for _, item := range items {
+		assert.NilError(t, pool.ScheduleWith(t.Context(), scheduler, async.Func(func(context.Context) error {
+			mu.Lock()
+			defer mu.Unlock()
+			order = append(order, item)
+			return nil
+		}), options(item)...))
+	}
+
+	// let the dispatcher take all the runners before releasing the worker
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wait()
+	return order
+}
+
+func TestFairSchedulingPriority(t *testing.T) {
+	order := fairRun(t, nil, []string{"low", "high", "normal", "high"}, func(item string) []pool.ScheduleOption {
+		return []pool.ScheduleOption{pool.WithPriority(map[string]int{"low": -1, "normal": 0, "high": 1}[item])}
+	})
+	assert.DeepEqual(t, order, []string{"high", "high", "normal", "low"})
+}
+
+func TestFairSchedulingFairness(t *testing.T) {
+	items := []string{"noisy", "noisy", "noisy", "noisy", "noisy", "noisy", "quiet", "quiet"}
+	keyOf := func(item string) []pool.ScheduleOption {
+		return []pool.ScheduleOption{pool.WithFairnessKey(item)}
+	}
+
+	// the runners of quiet are not queued behind all the runners of noisy
+	order := fairRun(t, nil, items, keyOf)
+	assert.Equal(t, count(order[:4], "quiet"), 2)
+
+	// with twice the weight, quiet gets two turns for each turn of noisy
+	order = fairRun(t, map[string]int{"quiet": 2}, append(items, "quiet", "quiet"), keyOf)
+	assert.Equal(t, count(order[:6], "quiet"), 4)
+}
+
+func count(items []string, item string) int {
+	n := 0
+	for _, i := range items {
+		if i == item {
+			n++
+		}
+	}
+	return n
+}
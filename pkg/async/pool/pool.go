@@ -85,7 +85,7 @@ var RejectWhenFull = ScheduleBehavior(func(ctx context.Context, queue chan unit,
 	select {
 	case <-ctx.Done():
 		return r.Run(ctx)
-	case queue <- unit{Context: ctx, Runner: r}:
+	case queue <- unit{Context: ctx, Runner: r, Scheduled: time.Now()}:
 		return nil
 	default:
 		cancel(orerr.LimitExceededError{
@@ -101,7 +101,7 @@ var WaitWhenFull = ScheduleBehavior(func(ctx context.Context, queue chan unit, r
 	select {
 	case <-ctx.Done():
 		return r.Run(ctx)
-	case queue <- unit{Context: ctx, Runner: r}:
+	case queue <- unit{Context: ctx, Runner: r, Scheduled: time.Now()}:
 		return nil
 	}
 })
@@ -123,6 +123,18 @@ type Options struct {
 
 	// Pool name for logging reasons
 	Name string
+
+	// FairScheduling runs the queued runners by priority and fairness
+	// key instead of in FIFO order, see FairScheduling
+	FairScheduling bool
+
+	// FairnessWeights is the share of every fairness key when
+	// FairScheduling is enabled
+	FairnessWeights map[string]int
+
+	// adaptive is set when the size is an AdaptiveSize, which is fed
+	// with the wait time and latency of the tasks
+	adaptive *AdaptiveSize
 }
 
 // Pool structure
@@ -134,6 +146,19 @@ type Pool struct {
 	opts    *Options
 	queue   chan unit
 	wg      *sync.WaitGroup
+
+	// intake is the channel runners are scheduled on. It is the queue,
+	// unless FairScheduling is enabled in which case runners go through
+	// the dispatcher.
+	intake chan unit
+
+	closeOnce sync.Once
+	stats     poolStats
+
+	// drainMu protects draining, Schedule holds it for reading until
+	// the runner is counted as queued
+	drainMu  sync.RWMutex
+	draining bool
 }
 
 // New creates new instance of Pool and start goroutine that will spawn the workers
@@ -167,6 +192,15 @@ func New(ctx context.Context, options ...Option) *Pool {
 		context: ctx,
 		closed:  make(chan struct{}),
 	}
+	p.intake = p.queue
+	if opts.adaptive != nil {
+		opts.adaptive.attach(&p.stats)
+	}
+	if opts.FairScheduling {
+		p.queue = make(chan unit)
+		p.wg.Add(1)
+		go p.dispatch(ctx)
+	}
 	// spawn initial workers synchronously
 	cancellations := p.spawnWorkers(ctx, p.opts.Size())
 	p.wg.Add(1)
@@ -202,12 +236,17 @@ func (p *Pool) run(ctx context.Context, cancellations cancellations) {
 			cancellations = append(cancellations, p.spawnWorkers(ctx, delta)...)
 		}
 		if delta != 0 {
+			var decision log.F
+			if p.opts.adaptive != nil {
+				decision = p.opts.adaptive.lastDecision()
+			}
 			log.Info(ctx, "async.pool resized",
 				log.F{
 					"pool":     p.opts.Name,
 					"size":     len(cancellations),
 					"previous": prevSize,
 				},
+				decision,
 			)
 		}
 
@@ -228,8 +267,16 @@ func (p *Pool) worker(ctx context.Context) {
 	for {
 		select {
 		case u = <-p.queue:
+			p.stats.queued.Add(-1)
+			p.stats.running.Add(1)
+			start := time.Now()
 			//nolint:errcheck // Why: best effort
-			_ = u.Runner.Run(u.Context)
+			_ = u.Runner.(*scheduled).Runner.Run(u.Context)
+			p.stats.running.Add(-1)
+			p.stats.completed.Add(1)
+			if p.opts.adaptive != nil {
+				p.opts.adaptive.observe(start.Sub(u.Scheduled), time.Since(start))
+			}
 		case <-p.closed:
 			return
 		case <-ctx.Done():
@@ -240,8 +287,10 @@ func (p *Pool) worker(ctx context.Context) {
 
 // Close blocks until all workers finshes current items and terminates
 func (p *Pool) Close() {
-	p.cancel(&orerr.ShutdownError{Err: context.Canceled})
-	close(p.closed)
+	p.closeOnce.Do(func() {
+		p.cancel(&orerr.ShutdownError{Err: context.Canceled})
+		close(p.closed)
+	})
 	p.wg.Wait()
 }
 
@@ -258,13 +307,22 @@ func (p *Pool) Close() {
 // README:
 // https://github.com/getoutreach/gobox/tree/main/pkg/async/pool/README.md
 func (p *Pool) Schedule(ctx context.Context, r async.Runner) error {
+	p.drainMu.RLock()
 	// Check whether pool is alive
-	if p.context.Err() != nil {
+	if p.draining || p.context.Err() != nil {
+		p.drainMu.RUnlock()
+		p.stats.rejected.Add(1)
+		err := p.context.Err()
+		if err == nil {
+			err = &orerr.ShutdownError{Err: ErrDraining}
+		}
 		ctxErr, cancel := orerr.CancelWithError(ctx)
-		cancel(p.context.Err())
+		cancel(err)
 		return r.Run(ctxErr)
 	}
-	return p.opts.ScheduleBehavior(ctx, p.queue, r)
+	p.stats.queued.Add(1)
+	p.drainMu.RUnlock()
+	return p.opts.ScheduleBehavior(ctx, p.intake, &scheduled{Runner: r, stats: &p.stats})
 }
 
 type cancellations []context.CancelFunc
@@ -288,6 +346,9 @@ func (c cancellations) Shrink(by int) cancellations {
 type unit struct {
 	Context context.Context
 	Runner  async.Runner
+
+	// Scheduled is when the runner was queued
+	Scheduled time.Time
 }
 
 type loggingScheduler struct {
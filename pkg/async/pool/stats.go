@@ -0,0 +1,159 @@
+// Copyright 2026 Outreach Corporation. All Rights Reserved.
+
+// Description: Provides introspection and draining for the async pool
+
+package pool
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/getoutreach/gobox/pkg/async"
+	"github.com/getoutreach/gobox/pkg/orerr"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// drainPollInterval is how often Drain checks whether the pool is empty
+const drainPollInterval = 10 * time.Millisecond
+
+// ErrDraining is wrapped in the orerr.ShutdownError runners scheduled on
+// a draining pool are canceled with
+var ErrDraining = errors.New("pool is draining")
+
+// Stats is a snapshot of the activity of a pool
+type Stats struct {
+	// Queued is the number of runners waiting for a worker
+	Queued int64
+
+	// Running is the number of runners being run by a worker
+	Running int64
+
+	// Completed is the number of runners run by a worker
+	Completed int64
+
+	// Rejected is the number of runners which were not queued because
+	// the queue was full, or the pool closed or draining
+	Rejected int64
+
+	// TimedOut is the number of runners which were not queued because
+	// their context was done before there was room in the queue
+	TimedOut int64
+}
+
+// poolStats holds the counters behind Stats
+type poolStats struct {
+	queued    atomic.Int64
+	running   atomic.Int64
+	completed atomic.Int64
+	rejected  atomic.Int64
+	timedOut  atomic.Int64
+}
+
+// scheduled wraps the runners scheduled on the pool. Workers run the
+// wrapped runner, so Run is only called by a ScheduleBehavior when the
+// runner could not be queued.
+type scheduled struct {
+	async.Runner
+	stats *poolStats
+}
+
+// Run implements the Runner interface for runners which were not queued
+func (s *scheduled) Run(ctx context.Context) error {
+	s.stats.queued.Add(-1)
+	var limitErr orerr.LimitExceededError
+	if errors.As(ctx.Err(), &limitErr) {
+		s.stats.rejected.Add(1)
+	} else {
+		s.stats.timedOut.Add(1)
+	}
+	return s.Runner.Run(ctx)
+}
+
+// Stats returns a snapshot of the activity of the pool
+func (p *Pool) Stats() Stats {
+	return Stats{
+		Queued:    p.stats.queued.Load(),
+		Running:   p.stats.running.Load(),
+		Completed: p.stats.completed.Load(),
+		Rejected:  p.stats.rejected.Load(),
+		TimedOut:  p.stats.timedOut.Load(),
+	}
+}
+
+// Drain stops accepting runners and waits for the queued ones to be
+// run, then closes the pool. Runners scheduled from then on are run with
+// a context canceled with an orerr.ShutdownError wrapping ErrDraining,
+// as when the pool is closed.
+//
+// If ctx is done before the queue is empty, Drain returns its error and
+// the pool keeps draining; call Close to stop it.
+func (p *Pool) Drain(ctx context.Context) error {
+	// once the lock is acquired, every runner accepted before is counted
+	// as queued
+	p.drainMu.Lock()
+	p.draining = true
+	p.drainMu.Unlock()
+
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+	for p.stats.queued.Load() > 0 || p.stats.running.Load() > 0 {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	p.Close()
+	return nil
+}
+
+// Collector returns a Prometheus collector reporting the Stats of the
+// pool, labeled with its name. It must be registered by the caller:
+//
+//	prometheus.MustRegister(p.Collector())
+func (p *Pool) Collector() prometheus.Collector {
+	return &statsCollector{pool: p}
+}
+
+// statsCollector implements the collector returned by Pool.Collector
+type statsCollector struct {
+	pool *Pool
+}
+
+// statsDescs describes the metrics of a pool
+var statsDescs = struct { // nolint:gochecknoglobals // Why: cleaner to use everywhere
+	queued, running, completed, rejected, timedOut *prometheus.Desc
+}{
+	queued: prometheus.NewDesc("async_pool_queued", "The number of runners waiting for a worker",
+		[]string{"pool"}, nil),
+	running: prometheus.NewDesc("async_pool_running", "The number of runners being run by a worker",
+		[]string{"pool"}, nil),
+	completed: prometheus.NewDesc("async_pool_completed_total", "The number of runners run by a worker",
+		[]string{"pool"}, nil),
+	rejected: prometheus.NewDesc("async_pool_rejected_total", "The number of runners rejected by the pool",
+		[]string{"pool"}, nil),
+	timedOut: prometheus.NewDesc("async_pool_timed_out_total",
+		"The number of runners whose context was done before they were queued", []string{"pool"}, nil),
+}
+
+// Describe implements the prometheus.Collector interface
+func (c *statsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- statsDescs.queued
+	ch <- statsDescs.running
+	ch <- statsDescs.completed
+	ch <- statsDescs.rejected
+	ch <- statsDescs.timedOut
+}
+
+// Collect implements the prometheus.Collector interface
+func (c *statsCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.pool.Stats()
+	name := c.pool.opts.Name
+	ch <- prometheus.MustNewConstMetric(statsDescs.queued, prometheus.GaugeValue, float64(stats.Queued), name)
+	ch <- prometheus.MustNewConstMetric(statsDescs.running, prometheus.GaugeValue, float64(stats.Running), name)
+	ch <- prometheus.MustNewConstMetric(statsDescs.completed, prometheus.CounterValue, float64(stats.Completed), name)
+	ch <- prometheus.MustNewConstMetric(statsDescs.rejected, prometheus.CounterValue, float64(stats.Rejected), name)
+	ch <- prometheus.MustNewConstMetric(statsDescs.timedOut, prometheus.CounterValue, float64(stats.TimedOut), name)
+}
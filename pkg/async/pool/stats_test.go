@@ -0,0 +1,112 @@
+package pool_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/getoutreach/gobox/pkg/async"
+	"github.com/getoutreach/gobox/pkg/async/pool"
+	"github.com/getoutreach/gobox/pkg/orerr"
+	"github.com/prometheus/client_golang/prometheus"
+	"gotest.tools/v3/assert"
+)
+
+func TestDrainCompletesQueuedRunners(t *testing.T) {
+	p := pool.New(t.Context(), pool.ConstantSize(1), pool.BufferLength(10))
+	defer p.Close()
+
+	var ran atomic.Int32
+	for range 5 {
+		assert.NilError(t, p.Schedule(t.Context(), async.Func(func(context.Context) error {
+			time.Sleep(5 * time.Millisecond)
+			ran.Add(1)
+			return nil
+		})))
+	}
+
+	assert.NilError(t, p.Drain(t.Context()))
+	assert.Equal(t, ran.Load(), int32(5))
+
+	var scheduleErr error
+	//nolint:errcheck // Why: the error is checked through the runner context
+	p.Schedule(t.Context(), async.Func(func(ctx context.Context) error {
+		scheduleErr = ctx.Err()
+		return nil
+	}))
+	var shutdownErr *orerr.ShutdownError
+	assert.Assert(t, errors.As(scheduleErr, &shutdownErr))
+
+	stats := p.Stats()
+	assert.Equal(t, stats.Completed, int64(5))
+	assert.Equal(t, stats.Rejected, int64(1))
+	assert.Equal(t, stats.Queued, int64(0))
+	assert.Equal(t, stats.Running, int64(0))
+}
+
+func TestDrainTimeout(t *testing.T) {
+	p := pool.New(t.Context(), pool.ConstantSize(1))
+	defer p.Close()
+
+	release := make(chan struct{})
+	defer close(release)
+	assert.NilError(t, p.Schedule(t.Context(), async.Func(func(context.Context) error {
+		<-release
+		return nil
+	})))
+
+	ctx, cancel := context.WithTimeout(t.Context(), 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, p.Drain(ctx), context.DeadlineExceeded)
+	assert.Equal(t, p.Stats().Running, int64(1))
+}
+
+func TestStatsRejectedAndTimedOut(t *testing.T) {
+	p := pool.New(t.Context(), pool.ConstantSize(1), pool.BufferLength(1), pool.RejectWhenFull)
+	defer p.Close()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	nop := async.Func(func(context.Context) error { return nil })
+	assert.NilError(t, p.Schedule(t.Context(), async.Func(func(context.Context) error {
+		close(started)
+		<-release
+		return nil
+	})))
+	<-started
+	assert.NilError(t, p.Schedule(t.Context(), nop))
+	assert.NilError(t, p.Schedule(t.Context(), nop))
+
+	stats := p.Stats()
+	assert.Equal(t, stats.Running, int64(1))
+	assert.Equal(t, stats.Queued, int64(1))
+	assert.Equal(t, stats.Rejected, int64(1))
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	waiting := pool.New(t.Context(), pool.ConstantSize(0), pool.BufferLength(0))
+	defer waiting.Close()
+	assert.NilError(t, waiting.Schedule(ctx, nop))
+	assert.Equal(t, waiting.Stats().TimedOut, int64(1))
+}
+
+func TestStatsCollector(t *testing.T) {
+	p := pool.New(t.Context(), pool.ConstantSize(1), pool.OptionFunc(func(opts *pool.Options) { opts.Name = "collected" }))
+	defer p.Close()
+
+	reg := prometheus.NewRegistry()
+	assert.NilError(t, reg.Register(p.Collector()))
+	assert.NilError(t, p.Drain(t.Context()))
+
+	families, err := reg.Gather()
+	assert.NilError(t, err)
+	assert.Equal(t, len(families), 5)
+	for _, f := range families {
+		assert.Assert(t, strings.HasPrefix(f.GetName(), "async_pool_"))
+		assert.Equal(t, f.GetMetric()[0].GetLabel()[0].GetValue(), "collected")
+	}
+}
@@ -0,0 +1,270 @@
+// Copyright 2026 Outreach Corporation. All Rights Reserved.
+
+// Description: Provides client side rate limiters
+
+package async
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/getoutreach/gobox/pkg/orerr"
+	"github.com/getoutreach/gobox/pkg/statuscodes"
+)
+
+// Limiter limits how often something may happen.
+type Limiter interface {
+	// Allow reports whether an event may happen now, consuming the
+	// allowance if it does.
+	Allow() bool
+
+	// Wait blocks until an event may happen or the context is done. In
+	// the latter case, an error with the statuscodes.RateLimited status
+	// is returned.
+	Wait(ctx context.Context) error
+}
+
+// newRateLimitedError creates the error returned when a limiter rejects
+// an event, optionally wrapping the cause (e.g. the context error).
+func newRateLimitedError(cause error) error {
+	return orerr.NewErrorStatus(orerr.LimitExceededError{Kind: "Rate", Err: cause}, statuscodes.RateLimited)
+}
+
+// limiterState is the part shared by the limiter implementations: the
+// lock, the options and a channel closed whenever the limits change so
+// that waiters re-evaluate their delay.
+type limiterState struct {
+	mu      sync.Mutex
+	changed chan struct{}
+	opts    syncOptions
+}
+
+// notify wakes up all waiters, l.mu must be held
+func (l *limiterState) notify() {
+	close(l.changed)
+	l.changed = make(chan struct{})
+}
+
+// wait calls reserve until it returns a zero delay, sleeping the
+// returned delay in between.
+func (l *limiterState) wait(ctx context.Context, primitive string, reserve func(now time.Time) time.Duration) error {
+	start := time.Now()
+	for {
+		l.mu.Lock()
+		delay := reserve(time.Now())
+		changed := l.changed
+		l.mu.Unlock()
+		if delay == 0 {
+			l.opts.observe(primitive, start, nil)
+			return nil
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-changed:
+			timer.Stop()
+		case <-ctx.Done():
+			timer.Stop()
+			l.opts.observe(primitive, start, ctx.Err())
+			return newRateLimitedError(ctx.Err())
+		}
+	}
+}
+
+// TokenBucket is a token bucket rate limiter: it holds up to burst
+// tokens, refilled at rate tokens per second, and every event consumes
+// one token.
+//
+// Both the rate and the burst can be adjusted while the limiter is in
+// use.
+type TokenBucket struct {
+	limiterState
+
+	rate   float64
+	burst  int
+	tokens float64
+	last   time.Time
+}
+
+// NewTokenBucket creates a TokenBucket allowing rate events per second
+// with bursts of up to burst events. The bucket starts full.
+//
+// Since every event needs a whole token, a burst below 1 would block
+// every event forever; it is raised to 1.
+func NewTokenBucket(rate float64, burst int, options ...SyncOption) *TokenBucket {
+	burst = max(burst, 1)
+	return &TokenBucket{
+		limiterState: limiterState{changed: make(chan struct{}), opts: newSyncOptions(options)},
+		rate:         rate,
+		burst:        burst,
+		tokens:       float64(burst),
+		last:         time.Now(),
+	}
+}
+
+// SetRate changes the refill rate, in events per second
+func (b *TokenBucket) SetRate(rate float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refill(time.Now())
+	b.rate = rate
+	b.notify()
+}
+
+// SetBurst changes the maximum number of tokens in the bucket. As in
+// NewTokenBucket, a burst below 1 is raised to 1.
+func (b *TokenBucket) SetBurst(burst int) {
+	burst = max(burst, 1)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refill(time.Now())
+	b.burst = burst
+	b.tokens = math.Min(b.tokens, float64(burst))
+	b.notify()
+}
+
+// refill adds the tokens accumulated since the last refill, b.mu must
+// be held
+func (b *TokenBucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = math.Min(float64(b.burst), b.tokens+elapsed.Seconds()*b.rate)
+	}
+	b.last = now
+}
+
+// reserve takes a token if one is available, otherwise it returns how
+// long to wait for the next one. b.mu must be held.
+func (b *TokenBucket) reserve(now time.Time) time.Duration {
+	b.refill(now)
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0
+	}
+	if b.rate <= 0 {
+		// nothing will be refilled until the rate is changed
+		return time.Hour
+	}
+	// round up: a delay of 0 would mean the token was taken
+	return max(time.Duration(math.Ceil((1-b.tokens)/b.rate*float64(time.Second))), time.Nanosecond)
+}
+
+// Allow implements the Limiter interface
+func (b *TokenBucket) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.reserve(time.Now()) == 0
+}
+
+// Wait implements the Limiter interface
+func (b *TokenBucket) Wait(ctx context.Context) error {
+	return b.wait(ctx, "token_bucket", b.reserve)
+}
+
+// SlidingWindow is a rate limiter allowing at most limit events within
+// any window of the given duration.
+//
+// It keeps the time of every event within the window, so it is best
+// suited for limits up to a few thousands of events per window. The
+// limit and the window can be adjusted while the limiter is in use.
+type SlidingWindow struct {
+	limiterState
+
+	limit  int
+	window time.Duration
+	events []time.Time
+}
+
+// NewSlidingWindow creates a SlidingWindow allowing limit events per window
+func NewSlidingWindow(limit int, window time.Duration, options ...SyncOption) *SlidingWindow {
+	return &SlidingWindow{
+		limiterState: limiterState{changed: make(chan struct{}), opts: newSyncOptions(options)},
+		limit:        limit,
+		window:       window,
+	}
+}
+
+// SetLimit changes the number of events allowed per window
+func (w *SlidingWindow) SetLimit(limit int, window time.Duration) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.limit = limit
+	w.window = window
+	w.notify()
+}
+
+// reserve records an event if the limit allows it, otherwise it
+// returns how long to wait for the oldest event to leave the window.
+// w.mu must be held.
+func (w *SlidingWindow) reserve(now time.Time) time.Duration {
+	cutoff := now.Add(-w.window)
+	expired := 0
+	for expired < len(w.events) && !w.events[expired].After(cutoff) {
+		expired++
+	}
+	w.events = w.events[expired:]
+
+	if len(w.events) < w.limit {
+		w.events = append(w.events, now)
+		return 0
+	}
+	if w.limit <= 0 {
+		return time.Hour
+	}
+	// the event that has to expire for a slot to free up
+	return w.events[len(w.events)-w.limit].Add(w.window).Sub(now)
+}
+
+// Allow implements the Limiter interface
+func (w *SlidingWindow) Allow() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.reserve(time.Now()) == 0
+}
+
+// Wait implements the Limiter interface
+func (w *SlidingWindow) Wait(ctx context.Context) error {
+	return w.wait(ctx, "sliding_window", w.reserve)
+}
+
+// WaitWhenLimited wraps r so that every Run waits for l first. If the
+// context ends while waiting, the RateLimited error from l.Wait is
+// returned and r does not run.
+func WaitWhenLimited(l Limiter, r Runner) Runner {
+	return &throttledRunner{Runner: r, throttle: func(ctx context.Context) error {
+		return l.Wait(ctx)
+	}}
+}
+
+// RejectWhenLimited wraps r so that Run fails right away with an error
+// carrying the statuscodes.RateLimited status when l does not allow it.
+func RejectWhenLimited(l Limiter, r Runner) Runner {
+	return &throttledRunner{Runner: r, throttle: func(context.Context) error {
+		if !l.Allow() {
+			return newRateLimitedError(nil)
+		}
+		return nil
+	}}
+}
+
+// throttledRunner implements the Runners returned by WaitWhenLimited
+// and RejectWhenLimited
+type throttledRunner struct {
+	Runner
+	throttle func(ctx context.Context) error
+}
+
+// Run implements the Runner interface
+func (t *throttledRunner) Run(ctx context.Context) error {
+	if err := t.throttle(ctx); err != nil {
+		return err
+	}
+	return t.Runner.Run(ctx)
+}
+
+// Close implements the Closer interface by closing the wrapped runner
+func (t *throttledRunner) Close(ctx context.Context) error {
+	return RunClose(ctx, t.Runner)
+}
@@ -0,0 +1,25 @@
+//go:build !or_e2e
+
+package async
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestTokenBucketReserveFractionalToken(t *testing.T) {
+	b := NewTokenBucket(1e9, 1)
+	now := b.last
+
+	// a token just short of whole must not be taken, even though the
+	// wait for the rest of it is below a nanosecond
+	b.tokens = 0.9999
+	assert.Equal(t, b.reserve(now), time.Nanosecond)
+	assert.Equal(t, b.tokens, 0.9999)
+
+	b.rate = 1
+	b.tokens = 0.5
+	assert.Equal(t, b.reserve(now), 500*time.Millisecond)
+}
@@ -0,0 +1,113 @@
+//go:build !or_e2e
+
+package async_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/getoutreach/gobox/pkg/async"
+	"github.com/getoutreach/gobox/pkg/orerr"
+	"github.com/getoutreach/gobox/pkg/statuscodes"
+	"gotest.tools/v3/assert"
+)
+
+func TestTokenBucketAllow(t *testing.T) {
+	b := async.NewTokenBucket(0, 2)
+	assert.Assert(t, b.Allow())
+	assert.Assert(t, b.Allow())
+	assert.Assert(t, !b.Allow())
+
+	b.SetRate(1000)
+	time.Sleep(5 * time.Millisecond)
+	assert.Assert(t, b.Allow())
+}
+
+func TestTokenBucketWait(t *testing.T) {
+	b := async.NewTokenBucket(200, 1)
+	assert.NilError(t, b.Wait(t.Context()))
+
+	start := time.Now()
+	assert.NilError(t, b.Wait(t.Context()))
+	assert.Assert(t, time.Since(start) >= 3*time.Millisecond)
+}
+
+func TestTokenBucketWaitCanceled(t *testing.T) {
+	b := async.NewTokenBucket(0, 1)
+	assert.Assert(t, b.Allow())
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+	defer cancel()
+
+	err := b.Wait(ctx)
+	assert.Assert(t, orerr.IsErrorStatusCode(err, statuscodes.RateLimited))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestTokenBucketBurstBelowOne(t *testing.T) {
+	b := async.NewTokenBucket(1000, 0)
+	assert.NilError(t, b.Wait(t.Context()))
+
+	b.SetBurst(-1)
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+	assert.NilError(t, b.Wait(ctx))
+}
+
+func TestTokenBucketSetRateWakesWaiters(t *testing.T) {
+	b := async.NewTokenBucket(0, 1)
+	assert.Assert(t, b.Allow())
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		b.SetRate(1000)
+	}()
+
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+	assert.NilError(t, b.Wait(ctx))
+}
+
+func TestSlidingWindow(t *testing.T) {
+	w := async.NewSlidingWindow(2, 20*time.Millisecond)
+	assert.Assert(t, w.Allow())
+	assert.Assert(t, w.Allow())
+	assert.Assert(t, !w.Allow())
+
+	start := time.Now()
+	assert.NilError(t, w.Wait(t.Context()))
+	assert.Assert(t, time.Since(start) >= 10*time.Millisecond)
+
+	w.SetLimit(10, 20*time.Millisecond)
+	assert.Assert(t, w.Allow())
+}
+
+func TestRejectWhenLimited(t *testing.T) {
+	runs := 0
+	r := async.RejectWhenLimited(async.NewSlidingWindow(1, time.Hour), async.Func(func(context.Context) error {
+		runs++
+		return nil
+	}))
+
+	assert.NilError(t, r.Run(t.Context()))
+	err := r.Run(t.Context())
+	assert.Assert(t, orerr.IsErrorStatusCode(err, statuscodes.RateLimited))
+	assert.Assert(t, async.IsRetryableError(err))
+	assert.Equal(t, runs, 1)
+}
+
+func TestWaitWhenLimited(t *testing.T) {
+	runs := 0
+	r := async.WaitWhenLimited(async.NewTokenBucket(0, 1), async.Func(func(context.Context) error {
+		runs++
+		return nil
+	}))
+
+	assert.NilError(t, r.Run(t.Context()))
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+	defer cancel()
+	err := r.Run(ctx)
+	assert.Assert(t, orerr.IsErrorStatusCode(err, statuscodes.RateLimited))
+	assert.Equal(t, runs, 1)
+}
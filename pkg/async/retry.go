@@ -0,0 +1,219 @@
+// Copyright 2026 Outreach Corporation. All Rights Reserved.
+
+// Description: Provides a retrying Runner with configurable backoff
+
+package async
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+
+	"github.com/getoutreach/gobox/pkg/log"
+	"github.com/getoutreach/gobox/pkg/orerr"
+	"github.com/getoutreach/gobox/pkg/statuscodes"
+	"github.com/getoutreach/gobox/pkg/trace"
+)
+
+// BackoffPolicy computes how long to wait before the next attempt.
+//
+// The attempt argument is the number of attempts made so far, so the
+// delay before the second attempt is computed with attempt == 1.
+type BackoffPolicy interface {
+	Next(attempt int) time.Duration
+}
+
+// BackoffFunc is a helper that implements the BackoffPolicy interface
+type BackoffFunc func(attempt int) time.Duration
+
+// Next implements the BackoffPolicy interface
+func (f BackoffFunc) Next(attempt int) time.Duration {
+	return f(attempt)
+}
+
+// ConstantBackoff waits the same duration between every attempt.
+func ConstantBackoff(d time.Duration) BackoffFunc {
+	return func(int) time.Duration {
+		return d
+	}
+}
+
+// ExponentialBackoff doubles the delay after every attempt, starting
+// at base and never exceeding maxDelay.
+func ExponentialBackoff(base, maxDelay time.Duration) BackoffFunc {
+	return func(attempt int) time.Duration {
+		d := base
+		for i := 1; i < attempt; i++ {
+			d *= 2
+			if d >= maxDelay || d <= 0 {
+				return maxDelay
+			}
+		}
+		return min(d, maxDelay)
+	}
+}
+
+// JitteredBackoff is ExponentialBackoff with "full jitter": the delay
+// is picked uniformly at random between zero and the exponential delay.
+// This spreads out retries from many clients failing at the same time.
+func JitteredBackoff(base, maxDelay time.Duration) BackoffFunc {
+	exp := ExponentialBackoff(base, maxDelay)
+	return func(attempt int) time.Duration {
+		d := exp(attempt)
+		if d <= 0 {
+			return 0
+		}
+		return rand.N(d + 1) //nolint:gosec // Why: jitter does not need a secure random source
+	}
+}
+
+// RetryOptions configures the behavior of Retry
+type RetryOptions struct {
+	// Backoff computes the delay between attempts. Defaults to
+	// JitteredBackoff(100ms, 10s).
+	Backoff BackoffPolicy
+
+	// MaxAttempts is the maximum number of attempts, including the
+	// first one. Zero means no limit.
+	MaxAttempts int
+
+	// MaxElapsedTime stops retrying once the next attempt would start
+	// after this much time has passed since the first one. Zero means
+	// no limit.
+	MaxElapsedTime time.Duration
+
+	// IsRetryable decides whether an error should be retried. Defaults
+	// to IsRetryableError.
+	IsRetryable func(err error) bool
+
+	// Name is used for the trace span events recorded on every attempt
+	Name string
+}
+
+// RetryOption configures a retrying Runner
+type RetryOption func(*RetryOptions)
+
+// WithBackoff sets the backoff policy used between attempts
+func WithBackoff(b BackoffPolicy) RetryOption {
+	return func(opts *RetryOptions) {
+		opts.Backoff = b
+	}
+}
+
+// WithMaxAttempts limits the number of attempts, including the first one
+func WithMaxAttempts(n int) RetryOption {
+	return func(opts *RetryOptions) {
+		opts.MaxAttempts = n
+	}
+}
+
+// WithMaxElapsedTime limits the total time spent retrying
+func WithMaxElapsedTime(d time.Duration) RetryOption {
+	return func(opts *RetryOptions) {
+		opts.MaxElapsedTime = d
+	}
+}
+
+// WithRetryableFunc overrides how errors are classified as retryable
+func WithRetryableFunc(f func(err error) bool) RetryOption {
+	return func(opts *RetryOptions) {
+		opts.IsRetryable = f
+	}
+}
+
+// WithRetryName sets the name used for the attempt span events
+func WithRetryName(name string) RetryOption {
+	return func(opts *RetryOptions) {
+		opts.Name = name
+	}
+}
+
+// retryableStatusCodes are the status codes which are expected to
+// succeed if the call is made again later.
+var retryableStatusCodes = []statuscodes.StatusCode{
+	statuscodes.Unavailable,
+	statuscodes.RateLimited,
+	statuscodes.DeadlineExceeded,
+}
+
+// IsRetryableError returns true if err was marked with orerr.Retryable
+// or carries a status code that is expected to be transient
+// (Unavailable, RateLimited or DeadlineExceeded).
+func IsRetryableError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if orerr.IsRetryable(err) {
+		return true
+	}
+	for _, code := range retryableStatusCodes {
+		if orerr.IsErrorStatusCode(err, code) {
+			return true
+		}
+	}
+	return false
+}
+
+// Retry wraps r into a Runner which retries retryable errors.
+//
+// Only errors accepted by IsRetryableError (or the function provided
+// via WithRetryableFunc) are retried, everything else is returned
+// right away. Sleeping between attempts goes through Sleep so context
+// cancellation is respected; when the context ends, the last error is
+// returned.
+//
+//	async.Run(ctx, async.Retry(runner,
+//	    async.WithBackoff(async.ExponentialBackoff(time.Second, time.Minute)),
+//	    async.WithMaxAttempts(5),
+//	))
+func Retry(r Runner, options ...RetryOption) Runner {
+	opts := &RetryOptions{
+		Backoff:     JitteredBackoff(100*time.Millisecond, 10*time.Second),
+		IsRetryable: IsRetryableError,
+		Name:        "async.retry",
+	}
+	for _, o := range options {
+		o(opts)
+	}
+	return &retryRunner{Runner: r, opts: opts}
+}
+
+// retryRunner implements the Runner returned by Retry
+type retryRunner struct {
+	Runner
+	opts *RetryOptions
+}
+
+// Run implements the Runner interface
+func (r *retryRunner) Run(ctx context.Context) error {
+	start := time.Now()
+	for attempt := 1; ; attempt++ {
+		err := r.Runner.Run(ctx)
+		trace.SendEvent(ctx, r.opts.Name, log.F{
+			"attempt": attempt,
+			"success": err == nil,
+		})
+		if err == nil || !r.opts.IsRetryable(err) {
+			return err
+		}
+
+		if r.opts.MaxAttempts > 0 && attempt >= r.opts.MaxAttempts {
+			return err
+		}
+
+		delay := r.opts.Backoff.Next(attempt)
+		if r.opts.MaxElapsedTime > 0 && time.Since(start)+delay > r.opts.MaxElapsedTime {
+			return err
+		}
+
+		Sleep(ctx, delay)
+		if ctx.Err() != nil {
+			return err
+		}
+	}
+}
+
+// Close implements the Closer interface by closing the wrapped runner
+func (r *retryRunner) Close(ctx context.Context) error {
+	return RunClose(ctx, r.Runner)
+}
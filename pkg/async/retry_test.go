@@ -0,0 +1,103 @@
+//go:build !or_e2e
+
+package async_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/getoutreach/gobox/pkg/async"
+	"github.com/getoutreach/gobox/pkg/orerr"
+	"github.com/getoutreach/gobox/pkg/statuscodes"
+	"gotest.tools/v3/assert"
+)
+
+func TestRetryRetryableErrors(t *testing.T) {
+	attempts := 0
+	r := async.Retry(async.Func(func(context.Context) error {
+		attempts++
+		if attempts < 3 {
+			return orerr.Retryable(errors.New("try again"))
+		}
+		return nil
+	}), async.WithBackoff(async.ConstantBackoff(time.Millisecond)))
+
+	assert.NilError(t, r.Run(t.Context()))
+	assert.Equal(t, attempts, 3)
+}
+
+func TestRetryStatusCodes(t *testing.T) {
+	for _, code := range []statuscodes.StatusCode{
+		statuscodes.Unavailable,
+		statuscodes.RateLimited,
+		statuscodes.DeadlineExceeded,
+	} {
+		assert.Assert(t, async.IsRetryableError(orerr.NewErrorStatus(errors.New("x"), code)), code)
+	}
+	assert.Assert(t, !async.IsRetryableError(orerr.NewErrorStatus(errors.New("x"), statuscodes.BadRequest)))
+	assert.Assert(t, !async.IsRetryableError(errors.New("x")))
+	assert.Assert(t, !async.IsRetryableError(nil))
+}
+
+func TestRetryNonRetryable(t *testing.T) {
+	attempts := 0
+	r := async.Retry(async.Func(func(context.Context) error {
+		attempts++
+		return errors.New("permanent")
+	}), async.WithBackoff(async.ConstantBackoff(time.Millisecond)))
+
+	assert.ErrorContains(t, r.Run(t.Context()), "permanent")
+	assert.Equal(t, attempts, 1)
+}
+
+func TestRetryMaxAttempts(t *testing.T) {
+	attempts := 0
+	r := async.Retry(async.Func(func(context.Context) error {
+		attempts++
+		return orerr.Retryable(errors.New("try again"))
+	}), async.WithBackoff(async.ConstantBackoff(time.Millisecond)), async.WithMaxAttempts(4))
+
+	assert.ErrorContains(t, r.Run(t.Context()), "try again")
+	assert.Equal(t, attempts, 4)
+}
+
+func TestRetryMaxElapsedTime(t *testing.T) {
+	attempts := 0
+	r := async.Retry(async.Func(func(context.Context) error {
+		attempts++
+		return orerr.Retryable(errors.New("try again"))
+	}), async.WithBackoff(async.ConstantBackoff(time.Hour)), async.WithMaxElapsedTime(time.Minute))
+
+	assert.ErrorContains(t, r.Run(t.Context()), "try again")
+	assert.Equal(t, attempts, 1)
+}
+
+func TestRetryContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithTimeout(t.Context(), 20*time.Millisecond)
+	defer cancel()
+
+	r := async.Retry(async.Func(func(context.Context) error {
+		return orerr.Retryable(errors.New("try again"))
+	}), async.WithBackoff(async.ConstantBackoff(time.Hour)))
+
+	start := time.Now()
+	assert.ErrorContains(t, r.Run(ctx), "try again")
+	assert.Assert(t, time.Since(start) < time.Minute)
+}
+
+func TestBackoffPolicies(t *testing.T) {
+	exp := async.ExponentialBackoff(time.Second, 5*time.Second)
+	assert.Equal(t, exp.Next(1), time.Second)
+	assert.Equal(t, exp.Next(2), 2*time.Second)
+	assert.Equal(t, exp.Next(3), 4*time.Second)
+	assert.Equal(t, exp.Next(4), 5*time.Second)
+	assert.Equal(t, exp.Next(100), 5*time.Second)
+
+	jitter := async.JitteredBackoff(time.Second, 5*time.Second)
+	for i := 1; i < 10; i++ {
+		d := jitter.Next(i)
+		assert.Assert(t, d >= 0 && d <= exp.Next(i))
+	}
+}
@@ -0,0 +1,188 @@
+// Copyright 2026 Outreach Corporation. All Rights Reserved.
+
+// Description: Provides a runner which executes jobs on a schedule
+
+package async
+
+import (
+	"context"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/getoutreach/gobox/pkg/log"
+	"github.com/getoutreach/gobox/pkg/trace"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// missedRuns registers the async_scheduler_missed_runs_total metric
+// for reporting scheduled runs which did not happen.
+var missedRuns = promauto.NewCounterVec( // nolint:gochecknoglobals // Why: cleaner to use everywhere
+	prometheus.CounterOpts{
+		Name: "async_scheduler_missed_runs_total",
+		Help: "The number of scheduled runs which were skipped or missed",
+	},
+	[]string{"name", "reason"}, // Labels
+)
+
+// OverlapPolicy decides what happens when a scheduled run is due while
+// the previous one is still running.
+type OverlapPolicy int
+
+const (
+	// SkipIfRunning skips the run and records it as missed
+	SkipIfRunning OverlapPolicy = iota
+
+	// AllowOverlap starts the run concurrently with the previous one
+	AllowOverlap
+)
+
+// SchedulerOptions configures a Scheduler
+type SchedulerOptions struct {
+	// Jitter delays every run by a random duration in [0, Jitter)
+	Jitter time.Duration
+
+	// Location is the time zone the schedule is evaluated in. Defaults
+	// to UTC.
+	Location *time.Location
+
+	// Overlap decides what happens when a run is due while the previous
+	// one is still running. Defaults to SkipIfRunning.
+	Overlap OverlapPolicy
+}
+
+// SchedulerOption configures a Scheduler
+type SchedulerOption func(*SchedulerOptions)
+
+// WithJitter delays every run by a random duration in [0, d)
+func WithJitter(d time.Duration) SchedulerOption {
+	return func(opts *SchedulerOptions) {
+		opts.Jitter = d
+	}
+}
+
+// WithLocation evaluates the schedule in the given time zone
+func WithLocation(loc *time.Location) SchedulerOption {
+	return func(opts *SchedulerOptions) {
+		opts.Location = loc
+	}
+}
+
+// WithOverlapPolicy sets what happens when a run is due while the
+// previous one is still running
+func WithOverlapPolicy(p OverlapPolicy) SchedulerOption {
+	return func(opts *SchedulerOptions) {
+		opts.Overlap = p
+	}
+}
+
+// Scheduler runs a Runner according to a Schedule.
+//
+// Every run goes through trace.StartCall with the scheduled time set
+// (see trace.WithScheduledTime), so the wait_time of the call log shows
+// how late the run started. Runs that are skipped because the previous
+// run is still running, or that were missed because the scheduler fell
+// behind, are logged, counted in Missed and reported in the
+// async_scheduler_missed_runs_total metric.
+//
+// Scheduler implements Runner, Run blocks until the context is canceled:
+//
+//	async.Run(ctx, async.NewScheduler("nightly-report",
+//	    async.MustParseCron("0 2 * * *"), reportRunner,
+//	    async.WithJitter(time.Minute),
+//	))
+type Scheduler struct {
+	Name string
+
+	schedule Schedule
+	runner   Runner
+	opts     *SchedulerOptions
+	running  atomic.Int32
+	missed   atomic.Int64
+}
+
+// NewScheduler creates a Scheduler which runs r according to s
+func NewScheduler(name string, s Schedule, r Runner, options ...SchedulerOption) *Scheduler {
+	opts := &SchedulerOptions{Location: time.UTC, Overlap: SkipIfRunning}
+	for _, o := range options {
+		o(opts)
+	}
+	return &Scheduler{Name: name, schedule: s, runner: r, opts: opts}
+}
+
+// Missed returns the number of runs which were skipped or missed
+func (s *Scheduler) Missed() int64 {
+	return s.missed.Load()
+}
+
+// Run implements the Runner interface. It waits for runs that are in
+// progress before returning.
+func (s *Scheduler) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	next := s.schedule.Next(time.Now().In(s.opts.Location))
+	for !next.IsZero() {
+		at := next
+		if s.opts.Jitter > 0 {
+			at = at.Add(rand.N(s.opts.Jitter)) //nolint:gosec // Why: jitter does not need a secure random source
+		}
+
+		SleepUntil(ctx, at)
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		if s.opts.Overlap == SkipIfRunning && s.running.Load() > 0 {
+			s.miss(ctx, "still_running", 1)
+		} else {
+			s.running.Add(1)
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer s.running.Add(-1)
+				s.invoke(ctx, at)
+			}()
+		}
+
+		// activations which passed while we were waiting are missed
+		now := time.Now()
+		next = s.schedule.Next(next)
+		missed := 0
+		for !next.IsZero() && next.Before(now) {
+			missed++
+			next = s.schedule.Next(next)
+		}
+		if missed > 0 {
+			s.miss(ctx, "behind", missed)
+		}
+	}
+	return nil
+}
+
+// invoke runs the runner once as a call scheduled at the given time
+func (s *Scheduler) invoke(ctx context.Context, scheduled time.Time) {
+	ctx = trace.StartCall(ctx, s.Name, trace.WithScheduledTime(scheduled))
+	defer trace.EndCall(ctx)
+
+	//nolint:errcheck // Why: the error is reported by trace.EndCall
+	trace.SetCallStatus(ctx, s.runner.Run(ctx))
+}
+
+// miss records n missed runs
+func (s *Scheduler) miss(ctx context.Context, reason string, n int) {
+	s.missed.Add(int64(n))
+	missedRuns.WithLabelValues(s.Name, reason).Add(float64(n))
+	log.Warn(ctx, "async.scheduler missed runs", log.F{
+		"scheduler": s.Name,
+		"reason":    reason,
+		"missed":    n,
+	})
+}
+
+// Close implements the Closer interface by closing the scheduled runner
+func (s *Scheduler) Close(ctx context.Context) error {
+	return RunClose(ctx, s.runner)
+}
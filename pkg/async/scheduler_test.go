@@ -0,0 +1,76 @@
+//go:build !or_e2e
+
+package async_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/getoutreach/gobox/pkg/async"
+	"gotest.tools/v3/assert"
+)
+
+func TestSchedulerRunsOnSchedule(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	var runs atomic.Int32
+	s := async.NewScheduler("test", async.Every(5*time.Millisecond), async.Func(func(context.Context) error {
+		if runs.Add(1) == 3 {
+			cancel()
+		}
+		return nil
+	}))
+
+	start := time.Now()
+	assert.NilError(t, s.Run(ctx))
+	assert.Assert(t, runs.Load() >= 3)
+
+	// a slow machine may miss activations, but runs and misses together
+	// never exceed the activations which passed
+	activations := int64(time.Since(start)/(5*time.Millisecond)) + 1
+	assert.Assert(t, int64(runs.Load())+s.Missed() <= activations,
+		"runs %d, missed %d, activations %d", runs.Load(), s.Missed(), activations)
+}
+
+func TestSchedulerSkipIfRunning(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	var runs atomic.Int32
+	s := async.NewScheduler("test", async.Every(5*time.Millisecond), async.Func(func(ctx context.Context) error {
+		runs.Add(1)
+		<-ctx.Done()
+		return nil
+	}))
+
+	go func() {
+		for s.Missed() < 2 {
+			time.Sleep(time.Millisecond)
+		}
+		cancel()
+	}()
+
+	assert.NilError(t, s.Run(ctx))
+	assert.Equal(t, runs.Load(), int32(1))
+	assert.Assert(t, s.Missed() >= 2)
+}
+
+func TestSchedulerAllowOverlap(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	var runs atomic.Int32
+	s := async.NewScheduler("test", async.Every(5*time.Millisecond), async.Func(func(ctx context.Context) error {
+		if runs.Add(1) == 3 {
+			cancel()
+		}
+		<-ctx.Done()
+		return nil
+	}), async.WithOverlapPolicy(async.AllowOverlap))
+
+	assert.NilError(t, s.Run(ctx))
+	assert.Assert(t, runs.Load() >= 3)
+}
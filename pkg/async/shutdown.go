@@ -0,0 +1,253 @@
+// Copyright 2026 Outreach Corporation. All Rights Reserved.
+
+// Description: Provides graceful shutdown of Tasks with a leak report
+
+package async
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LeakedTask describes a task that was still running when
+// Tasks.Shutdown gave up waiting for it.
+type LeakedTask struct {
+	// Name is the name of the Tasks which ran the task.
+	Name string
+
+	// Runner is the type of the task's Runner, such as *mypkg.Worker.
+	Runner string
+
+	// Caller is the file:line which started the task, outside of this
+	// package. It is only recorded with Tasks.ReportLeaks.
+	Caller string
+
+	Started time.Time
+
+	// Stack is the goroutine stack of the task. It is only recorded
+	// with Tasks.ReportLeaks.
+	Stack string
+}
+
+// MarshalLog implements the log.Marshaler interface
+func (l LeakedTask) MarshalLog(addField func(field string, value interface{})) {
+	addField("name", l.Name)
+	addField("runner", l.Runner)
+	addField("caller", l.Caller)
+	addField("started", l.Started)
+	addField("stack", l.Stack)
+}
+
+// ShutdownTimeoutError is returned by Tasks.Shutdown when tasks are
+// still running once the context is done. Err holds the context error.
+type ShutdownTimeoutError struct {
+	Tasks []LeakedTask
+	Err   error
+}
+
+// Error implements the err interface.
+func (e *ShutdownTimeoutError) Error() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d task(s) still running after shutdown: %v", len(e.Tasks), e.Err)
+	for _, task := range e.Tasks {
+		fmt.Fprintf(&sb, "\n\n%s: %s", task.Name, task.Runner)
+		if task.Caller != "" {
+			fmt.Fprintf(&sb, " started at %s", task.Caller)
+		}
+		fmt.Fprintf(&sb, " (started %s, running for %s)", task.Started.Format(time.RFC3339),
+			time.Since(task.Started).Round(time.Millisecond))
+		if task.Stack != "" {
+			fmt.Fprintf(&sb, "\n%s", task.Stack)
+		}
+	}
+	return sb.String()
+}
+
+// Unwrap returns the inner error.
+func (e *ShutdownTimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// MarshalLog implements the log.Marshaler interface
+func (e *ShutdownTimeoutError) MarshalLog(addField func(field string, value interface{})) {
+	addField("shutdown.leaked", len(e.Tasks))
+	for i, task := range e.Tasks {
+		addField("shutdown.tasks."+strconv.Itoa(i), task)
+	}
+}
+
+// runningTask is the bookkeeping kept for every task started by Tasks.
+// callers and goroutineID are only recorded with ReportLeaks.
+type runningTask struct {
+	runner      Runner
+	callers     []uintptr
+	started     time.Time
+	goroutineID string
+	cancel      context.CancelFunc
+}
+
+// Shutdown cancels the context of every task started by Run or Loop
+// and waits for them to return until ctx is done.
+//
+// Tasks started after Shutdown has been called start with a canceled
+// context. If tasks are still running when ctx is done, a
+// *ShutdownTimeoutError listing them is returned. It includes their
+// goroutine stack and the caller which started them when ReportLeaks is
+// set.
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+//	defer cancel()
+//	if err := tasks.Shutdown(ctx); err != nil {
+//	    log.Error(ctx, "tasks did not shut down", events.NewErrorInfo(err))
+//	}
+func (t *Tasks) Shutdown(ctx context.Context) error {
+	t.mu.Lock()
+	t.shutdown = true
+	for _, task := range t.running {
+		task.cancel()
+	}
+	t.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		t.WaitGroup.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+	}
+
+	t.mu.Lock()
+	tasks := make([]runningTask, 0, len(t.running))
+	for _, task := range t.running {
+		tasks = append(tasks, *task)
+	}
+	t.mu.Unlock()
+
+	if len(tasks) == 0 {
+		// the tasks finished in the meantime
+		return nil
+	}
+
+	sort.Slice(tasks, func(i, j int) bool {
+		return tasks[i].started.Before(tasks[j].started)
+	})
+	var stacks map[string]string
+	if t.ReportLeaks {
+		stacks = goroutineStacks()
+	}
+	err := &ShutdownTimeoutError{Err: ctx.Err()}
+	for _, task := range tasks {
+		err.Tasks = append(err.Tasks, LeakedTask{
+			Name:    t.Name,
+			Runner:  fmt.Sprintf("%T", task.runner),
+			Caller:  callerOutside(task.callers),
+			Started: task.started,
+			Stack:   stacks[task.goroutineID],
+		})
+	}
+	return err
+}
+
+// callers returns the program counters of the stack of the caller of Run
+// or Loop when ReportLeaks is set, see callerOutside.
+func (t *Tasks) callers() []uintptr {
+	if !t.ReportLeaks {
+		return nil
+	}
+	pcs := make([]uintptr, 16)
+	return pcs[:runtime.Callers(3, pcs)] // skip [Callers, callers, Run]
+}
+
+// callerOutside returns the file:line of the first frame outside of this
+// package, so that tasks started with async.Run name their caller rather
+// than Default.Run.
+func callerOutside(pcs []uintptr) string {
+	if len(pcs) == 0 {
+		return ""
+	}
+	frames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, asyncPackage+".") {
+			return fmt.Sprintf("%s:%d", frame.File, frame.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+// asyncPackage is the import path of this package
+const asyncPackage = "github.com/getoutreach/gobox/pkg/async"
+
+// track registers the calling goroutine as a running task of the runner,
+// started from the callers, and returns a context which is canceled on
+// Shutdown. The returned function must be called when the task finishes.
+func (t *Tasks) track(ctx context.Context, r Runner, callers []uintptr) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	task := &runningTask{runner: r, callers: callers, started: time.Now(), cancel: cancel}
+	if t.ReportLeaks {
+		task.goroutineID = goroutineID()
+	}
+
+	t.mu.Lock()
+	if t.shutdown {
+		cancel()
+	}
+	if t.running == nil {
+		t.running = map[uint64]*runningTask{}
+	}
+	t.nextID++
+	id := t.nextID
+	t.running[id] = task
+	t.mu.Unlock()
+
+	return ctx, func() {
+		cancel()
+		t.mu.Lock()
+		delete(t.running, id)
+		t.mu.Unlock()
+	}
+}
+
+// goroutineID returns the ID of the calling goroutine as printed in
+// stack traces.
+func goroutineID() string {
+	var buf [64]byte
+	b := buf[:runtime.Stack(buf[:], false)]
+	b = bytes.TrimPrefix(b, []byte("goroutine "))
+	if i := bytes.IndexByte(b, ' '); i >= 0 {
+		return string(b[:i])
+	}
+	return ""
+}
+
+// goroutineStacks returns the stacks of all goroutines keyed by their ID
+func goroutineStacks() map[string]string {
+	buf := make([]byte, 1<<16)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+
+	result := map[string]string{}
+	for _, stack := range strings.Split(string(buf), "\n\n") {
+		id, _, _ := strings.Cut(strings.TrimPrefix(stack, "goroutine "), " ")
+		result[id] = stack
+	}
+	return result
+}
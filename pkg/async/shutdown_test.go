@@ -0,0 +1,108 @@
+//go:build !or_e2e
+
+package async_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/getoutreach/gobox/pkg/async"
+	"gotest.tools/v3/assert"
+)
+
+func TestTasksShutdownCancelsTasks(t *testing.T) {
+	tasks := async.NewTasks("shutdown")
+	tasks.Run(context.Background(), async.Func(func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}))
+	tasks.Loop(context.Background(), async.Func(func(ctx context.Context) error {
+		async.Sleep(ctx, time.Hour)
+		return nil
+	}))
+
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+	assert.NilError(t, tasks.Shutdown(ctx))
+
+	// tasks started after shutdown get a canceled context
+	var runErr error
+	tasks.Run(context.Background(), async.Func(func(ctx context.Context) error {
+		runErr = ctx.Err()
+		return nil
+	}))
+	tasks.Wait()
+	assert.ErrorIs(t, runErr, context.Canceled)
+}
+
+func TestTasksShutdownReportsLeaks(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	tasks := async.NewTasks("stuck")
+	tasks.ReportLeaks = true
+	tasks.Run(context.Background(), async.Func(func(context.Context) error {
+		<-release
+		return nil
+	}))
+
+	ctx, cancel := context.WithTimeout(t.Context(), 20*time.Millisecond)
+	defer cancel()
+	err := tasks.Shutdown(ctx)
+
+	var leakErr *async.ShutdownTimeoutError
+	assert.Assert(t, errors.As(err, &leakErr))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, len(leakErr.Tasks), 1)
+	assert.Equal(t, leakErr.Tasks[0].Name, "stuck")
+	assert.Equal(t, leakErr.Tasks[0].Runner, "async.Func")
+	assert.Assert(t, strings.Contains(leakErr.Tasks[0].Caller, "shutdown_test.go:"), leakErr.Tasks[0].Caller)
+	assert.Assert(t, !leakErr.Tasks[0].Started.IsZero())
+	assert.Assert(t, leakErr.Tasks[0].Stack != "")
+	assert.ErrorContains(t, err, "TestTasksShutdownReportsLeaks")
+}
+
+func TestTasksShutdownReportsRunCaller(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	// async.Run goes through Default, the caller must still be this file
+	defaultTasks := async.Default
+	defer func() { async.Default = defaultTasks }()
+	async.Default = async.NewTasks("default")
+	async.Default.ReportLeaks = true
+	async.Run(context.Background(), async.Func(func(context.Context) error {
+		<-release
+		return nil
+	}))
+
+	ctx, cancel := context.WithTimeout(t.Context(), 20*time.Millisecond)
+	defer cancel()
+	var leakErr *async.ShutdownTimeoutError
+	assert.Assert(t, errors.As(async.Default.Shutdown(ctx), &leakErr))
+	assert.Equal(t, len(leakErr.Tasks), 1)
+	assert.Assert(t, strings.Contains(leakErr.Tasks[0].Caller, "shutdown_test.go:"), leakErr.Tasks[0].Caller)
+}
+
+func TestTasksShutdownWithoutReportLeaks(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	tasks := async.NewTasks("stuck")
+	tasks.Run(context.Background(), async.Func(func(context.Context) error {
+		<-release
+		return nil
+	}))
+
+	ctx, cancel := context.WithTimeout(t.Context(), 20*time.Millisecond)
+	defer cancel()
+	var leakErr *async.ShutdownTimeoutError
+	assert.Assert(t, errors.As(tasks.Shutdown(ctx), &leakErr))
+	assert.Equal(t, len(leakErr.Tasks), 1)
+	assert.Equal(t, leakErr.Tasks[0].Runner, "async.Func")
+	assert.Equal(t, leakErr.Tasks[0].Caller, "")
+	assert.Equal(t, leakErr.Tasks[0].Stack, "")
+}
@@ -0,0 +1,221 @@
+// Copyright 2026 Outreach Corporation. All Rights Reserved.
+
+// Description: Provides call deduplication and memoization
+
+package async
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/getoutreach/gobox/pkg/events"
+	"github.com/getoutreach/gobox/pkg/log"
+	"github.com/getoutreach/gobox/pkg/trace"
+)
+
+// GroupOptions configures a Group
+type GroupOptions struct {
+	// TTL memoizes results for the given duration after the call
+	// completes. Zero disables memoization. Only successful results
+	// are memoized unless MemoizeErrors is set.
+	TTL time.Duration
+
+	// MemoizeErrors memoizes failed calls for TTL too, so that callers
+	// of a failing key share its error rather than calling it again.
+	MemoizeErrors bool
+
+	// RetryOnError does not share errors: callers that were waiting on
+	// a call that failed retry it instead (one at a time, still
+	// deduplicated), and errors are never memoized. This mirrors
+	// RetryableOnce.
+	RetryOnError bool
+}
+
+// GroupOption configures a Group
+type GroupOption func(*GroupOptions)
+
+// WithTTL memoizes results for d after the call completes
+func WithTTL(d time.Duration) GroupOption {
+	return func(opts *GroupOptions) {
+		opts.TTL = d
+	}
+}
+
+// WithMemoizedErrors memoizes failed calls as well as successful ones,
+// see WithTTL
+func WithMemoizedErrors() GroupOption {
+	return func(opts *GroupOptions) {
+		opts.MemoizeErrors = true
+	}
+}
+
+// WithRetryOnError makes callers retry failed calls rather than
+// sharing the error
+func WithRetryOnError() GroupOption {
+	return func(opts *GroupOptions) {
+		opts.RetryOnError = true
+	}
+}
+
+// Group deduplicates concurrent calls with the same key: only the first
+// caller (the leader) executes the function, later callers (followers)
+// wait for and share its result.
+//
+// Every call starts a span named after the group. The spans of the
+// followers are linked to the span of the leader and marked with
+// singleflight.shared, so it is visible in a trace that a call
+// piggy-backed on another one.
+//
+//	users := async.NewGroup[string, *User]("users.lookup", async.WithTTL(time.Minute))
+//	user, err := users.Do(ctx, id, func(ctx context.Context) (*User, error) {
+//	    return fetchUser(ctx, id)
+//	})
+type Group[K comparable, V any] struct {
+	Name string
+
+	opts  *GroupOptions
+	mu    sync.Mutex
+	calls map[K]*flight[V]
+	memos map[K]*flight[V]
+
+	// sweepAt is when expired memos are next evicted, see complete
+	sweepAt time.Time
+}
+
+// flight is a call in progress or completed
+type flight[V any] struct {
+	done    chan struct{}
+	headers map[string][]string
+	val     V
+	err     error
+	expires time.Time
+}
+
+// NewGroup creates a new Group
+func NewGroup[K comparable, V any](name string, options ...GroupOption) *Group[K, V] {
+	opts := &GroupOptions{}
+	for _, o := range options {
+		o(opts)
+	}
+	return &Group[K, V]{
+		Name:  name,
+		opts:  opts,
+		calls: map[K]*flight[V]{},
+		memos: map[K]*flight[V]{},
+	}
+}
+
+// Do executes fn for the key unless a call for the same key is in
+// progress or memoized, in which case its result is returned instead.
+//
+// fn runs with the context of the leader. A follower whose context is
+// done stops waiting and returns the context error.
+func (g *Group[K, V]) Do(ctx context.Context, key K, fn func(ctx context.Context) (V, error)) (V, error) {
+	for {
+		g.mu.Lock()
+		if m, ok := g.memos[key]; ok {
+			if time.Now().Before(m.expires) {
+				g.mu.Unlock()
+				return m.val, m.err
+			}
+			delete(g.memos, key)
+		}
+
+		if f, ok := g.calls[key]; ok {
+			g.mu.Unlock()
+			retry, val, err := g.follow(ctx, f)
+			if retry {
+				continue
+			}
+			return val, err
+		}
+
+		// start the span of the leader before publishing the flight so
+		// that followers can always link to it
+		leaderCtx := trace.StartSpan(ctx, g.Name, log.F{"singleflight.shared": false})
+		f := &flight[V]{done: make(chan struct{}), headers: trace.ToHeaders(leaderCtx)}
+		g.calls[key] = f
+		g.mu.Unlock()
+
+		g.lead(leaderCtx, key, f, fn)
+		return f.val, f.err
+	}
+}
+
+// Forget drops the memoized result for key, if any. Calls in progress
+// are not affected.
+func (g *Group[K, V]) Forget(key K) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.memos, key)
+}
+
+// lead executes fn on behalf of all callers of key. ctx holds the span
+// of the leader, which is ended on return.
+func (g *Group[K, V]) lead(ctx context.Context, key K, f *flight[V], fn func(ctx context.Context) (V, error)) {
+	defer trace.End(ctx)
+	defer func() {
+		if r := recover(); r != nil {
+			f.err = &PanicError{Info: events.NewErrorInfoFromPanic(r)}
+			g.complete(key, f)
+			panic(r)
+		}
+		g.complete(key, f)
+	}()
+
+	f.val, f.err = fn(ctx)
+	//nolint:errcheck // Why: Error returns its input
+	trace.Error(ctx, f.err)
+}
+
+// complete publishes the result of f to its followers
+func (g *Group[K, V]) complete(key K, f *flight[V]) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.calls, key)
+	if g.opts.TTL > 0 && (f.err == nil || (g.opts.MemoizeErrors && !g.opts.RetryOnError)) {
+		now := time.Now()
+		f.expires = now.Add(g.opts.TTL)
+		g.memos[key] = f
+		g.sweep(now)
+	}
+	close(f.done)
+}
+
+// sweep evicts the expired memos, at most once per TTL so that keys
+// which are never called again do not stay in memory. The caller must
+// hold g.mu.
+func (g *Group[K, V]) sweep(now time.Time) {
+	if now.Before(g.sweepAt) {
+		return
+	}
+	g.sweepAt = now.Add(g.opts.TTL)
+	for key, m := range g.memos {
+		if !now.Before(m.expires) {
+			delete(g.memos, key)
+		}
+	}
+}
+
+// follow waits for the result of the leader. retry is true if the
+// leader failed and the caller should retry.
+func (g *Group[K, V]) follow(ctx context.Context, f *flight[V]) (retry bool, val V, err error) {
+	var opts []trace.SpanStartOption
+	if len(f.headers) > 0 {
+		opts = append(opts, trace.WithLink(f.headers))
+	}
+	ctx = trace.StartSpanWithOptions(ctx, g.Name, opts, log.F{"singleflight.shared": true})
+	defer trace.End(ctx)
+
+	select {
+	case <-f.done:
+	case <-ctx.Done():
+		return false, val, ctx.Err()
+	}
+
+	if f.err != nil && g.opts.RetryOnError {
+		return true, val, nil
+	}
+	return false, f.val, f.err
+}
@@ -0,0 +1,210 @@
+//go:build !or_e2e
+
+package async_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/getoutreach/gobox/pkg/async"
+	"gotest.tools/v3/assert"
+)
+
+// startFollowers calls g.Do n times concurrently and returns the
+// results once all calls are done.
+func startFollowers(t *testing.T, g *async.Group[string, int], n int,
+	fn func(context.Context) (int, error)) (vals []int, errs []error) {
+	t.Helper()
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err := g.Do(t.Context(), "key", fn)
+			mu.Lock()
+			vals = append(vals, v)
+			errs = append(errs, err)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return vals, errs
+}
+
+func TestGroupDeduplicates(t *testing.T) {
+	g := async.NewGroup[string, int]("test")
+
+	var calls atomic.Int32
+	vals, errs := startFollowers(t, g, 10, func(context.Context) (int, error) {
+		calls.Add(1)
+		time.Sleep(20 * time.Millisecond)
+		return 42, nil
+	})
+
+	assert.Equal(t, calls.Load(), int32(1))
+	for i := range vals {
+		assert.NilError(t, errs[i])
+		assert.Equal(t, vals[i], 42)
+	}
+
+	// without a TTL nothing is memoized
+	_, err := g.Do(t.Context(), "key", func(context.Context) (int, error) {
+		calls.Add(1)
+		return 0, nil
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, calls.Load(), int32(2))
+}
+
+func TestGroupSharesErrors(t *testing.T) {
+	g := async.NewGroup[string, int]("test")
+
+	var calls atomic.Int32
+	_, errs := startFollowers(t, g, 5, func(context.Context) (int, error) {
+		calls.Add(1)
+		time.Sleep(20 * time.Millisecond)
+		return 0, errors.New("failed")
+	})
+
+	assert.Equal(t, calls.Load(), int32(1))
+	for _, err := range errs {
+		assert.ErrorContains(t, err, "failed")
+	}
+}
+
+func TestGroupRetryOnError(t *testing.T) {
+	g := async.NewGroup[string, int]("test", async.WithRetryOnError())
+
+	var calls atomic.Int32
+	vals, errs := startFollowers(t, g, 5, func(context.Context) (int, error) {
+		time.Sleep(20 * time.Millisecond)
+		if calls.Add(1) == 1 {
+			return 0, errors.New("failed")
+		}
+		return 42, nil
+	})
+
+	// the leader sees its error, the followers retry
+	failed := 0
+	for i := range vals {
+		if errs[i] != nil {
+			failed++
+			continue
+		}
+		assert.Equal(t, vals[i], 42)
+	}
+	assert.Equal(t, failed, 1)
+	assert.Assert(t, calls.Load() >= 2)
+}
+
+func TestGroupTTL(t *testing.T) {
+	g := async.NewGroup[string, int]("test", async.WithTTL(time.Hour))
+
+	var calls atomic.Int32
+	fn := func(context.Context) (int, error) {
+		return int(calls.Add(1)), nil
+	}
+
+	v, err := g.Do(t.Context(), "key", fn)
+	assert.NilError(t, err)
+	assert.Equal(t, v, 1)
+
+	v, err = g.Do(t.Context(), "key", fn)
+	assert.NilError(t, err)
+	assert.Equal(t, v, 1)
+
+	v, err = g.Do(t.Context(), "other", fn)
+	assert.NilError(t, err)
+	assert.Equal(t, v, 2)
+
+	g.Forget("key")
+	v, err = g.Do(t.Context(), "key", fn)
+	assert.NilError(t, err)
+	assert.Equal(t, v, 3)
+}
+
+func TestGroupTTLRetryOnErrorSkipsErrors(t *testing.T) {
+	g := async.NewGroup[string, int]("test", async.WithTTL(time.Hour), async.WithRetryOnError())
+
+	_, err := g.Do(t.Context(), "key", func(context.Context) (int, error) {
+		return 0, errors.New("failed")
+	})
+	assert.ErrorContains(t, err, "failed")
+
+	v, err := g.Do(t.Context(), "key", func(context.Context) (int, error) {
+		return 42, nil
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, v, 42)
+}
+
+func TestGroupTTLErrors(t *testing.T) {
+	var calls atomic.Int32
+	fn := func(context.Context) (int, error) {
+		return 0, fmt.Errorf("failed %d", calls.Add(1))
+	}
+
+	// errors are not memoized by default
+	g := async.NewGroup[string, int]("test", async.WithTTL(time.Hour))
+	_, err := g.Do(t.Context(), "key", fn)
+	assert.Error(t, err, "failed 1")
+	_, err = g.Do(t.Context(), "key", fn)
+	assert.Error(t, err, "failed 2")
+
+	g = async.NewGroup[string, int]("test", async.WithTTL(time.Hour), async.WithMemoizedErrors())
+	_, err = g.Do(t.Context(), "key", fn)
+	assert.Error(t, err, "failed 3")
+	_, err = g.Do(t.Context(), "key", fn)
+	assert.Error(t, err, "failed 3")
+}
+
+func TestGroupTTLExpires(t *testing.T) {
+	g := async.NewGroup[string, int]("test", async.WithTTL(10*time.Millisecond))
+
+	var calls atomic.Int32
+	fn := func(context.Context) (int, error) {
+		return int(calls.Add(1)), nil
+	}
+
+	v, err := g.Do(t.Context(), "key", fn)
+	assert.NilError(t, err)
+	assert.Equal(t, v, 1)
+
+	time.Sleep(20 * time.Millisecond)
+	v, err = g.Do(t.Context(), "key", fn)
+	assert.NilError(t, err)
+	assert.Equal(t, v, 2)
+}
+
+func TestGroupFollowerContext(t *testing.T) {
+	g := async.NewGroup[string, int]("test")
+	release := make(chan struct{})
+	started := make(chan struct{})
+
+	go func() {
+		//nolint:errcheck // Why: only the follower is under test
+		g.Do(context.Background(), "key", func(context.Context) (int, error) {
+			close(started)
+			<-release
+			return 42, nil
+		})
+	}()
+	<-started
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+	defer cancel()
+	_, err := g.Do(ctx, "key", func(context.Context) (int, error) {
+		return 0, nil
+	})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	close(release)
+}
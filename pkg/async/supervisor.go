@@ -0,0 +1,394 @@
+// Copyright 2026 Outreach Corporation. All Rights Reserved.
+
+// Description: Provides a supervisor which restarts failed runners
+
+package async
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/getoutreach/gobox/pkg/events"
+	"github.com/getoutreach/gobox/pkg/log"
+	"github.com/getoutreach/gobox/pkg/trace"
+)
+
+// RestartStrategy decides which children get restarted when one of
+// them fails.
+type RestartStrategy int
+
+const (
+	// OneForOne only restarts the child that failed
+	OneForOne RestartStrategy = iota
+
+	// OneForAll stops and restarts every child when one of them fails
+	OneForAll
+
+	// RestForOne restarts the child that failed along with every child
+	// that was added after it
+	RestForOne
+)
+
+// ChildState is the lifecycle state of a supervised child
+type ChildState string
+
+const (
+	// ChildRunning means the child is currently running
+	ChildRunning ChildState = "running"
+
+	// ChildRestarting means the child failed and is waiting to be restarted
+	ChildRestarting ChildState = "restarting"
+
+	// ChildStopped means the child returned without an error or was
+	// stopped during shutdown
+	ChildStopped ChildState = "stopped"
+
+	// ChildFailed means the child failed and will not be restarted
+	// because the supervisor exhausted its restart budget
+	ChildFailed ChildState = "failed"
+)
+
+// ChildStatus reports the state of a supervised child
+type ChildStatus struct {
+	Name      string
+	State     ChildState
+	Restarts  int
+	StartedAt time.Time
+	LastError error
+}
+
+// MarshalLog implements the log.Marshaler interface
+func (c ChildStatus) MarshalLog(addField func(field string, value interface{})) {
+	addField("name", c.Name)
+	addField("state", string(c.State))
+	addField("restarts", c.Restarts)
+	if !c.StartedAt.IsZero() {
+		addField("started_at", c.StartedAt)
+	}
+	if c.LastError != nil {
+		addField("last_error", c.LastError.Error())
+	}
+}
+
+// SupervisorOptions configures a Supervisor
+type SupervisorOptions struct {
+	// Strategy decides which children are restarted when one fails.
+	// Defaults to OneForOne.
+	Strategy RestartStrategy
+
+	// MaxRestarts is the number of restarts allowed within
+	// RestartWindow. Once exceeded, the supervisor stops all of its
+	// children and returns the last error.
+	MaxRestarts int
+
+	// RestartWindow is the sliding window MaxRestarts applies to
+	RestartWindow time.Duration
+
+	// Backoff computes the delay before restarting, based on the
+	// number of restarts within the current window.
+	Backoff BackoffPolicy
+}
+
+// SupervisorOption configures a Supervisor
+type SupervisorOption func(*SupervisorOptions)
+
+// WithStrategy sets the restart strategy of the supervisor
+func WithStrategy(s RestartStrategy) SupervisorOption {
+	return func(opts *SupervisorOptions) {
+		opts.Strategy = s
+	}
+}
+
+// WithRestartBudget allows at most n restarts within window
+func WithRestartBudget(n int, window time.Duration) SupervisorOption {
+	return func(opts *SupervisorOptions) {
+		opts.MaxRestarts = n
+		opts.RestartWindow = window
+	}
+}
+
+// WithRestartBackoff sets the backoff policy used between restarts
+func WithRestartBackoff(b BackoffPolicy) SupervisorOption {
+	return func(opts *SupervisorOptions) {
+		opts.Backoff = b
+	}
+}
+
+// Supervisor owns a set of child Runners and restarts them when they
+// fail, Erlang style.
+//
+// Children are started in the order they were added and stopped in
+// the reverse order. A child returning nil is considered done and is
+// not restarted; a child returning an error is restarted according to
+// the RestartStrategy. When the context is canceled, or the restart
+// budget is exhausted, all children are stopped and closed via
+// RunClose.
+//
+// Supervisor implements Runner so supervisors can be nested:
+//
+//	sup := async.NewSupervisor("workers", async.WithStrategy(async.OneForAll))
+//	sup.Add("consumer", consumer)
+//	sup.Add("producer", producer)
+//	async.Run(ctx, sup)
+type Supervisor struct {
+	Name string
+
+	opts     *SupervisorOptions
+	mu       sync.Mutex
+	children []*supervisedChild
+}
+
+// supervisedChild tracks a single child of a Supervisor. All fields
+// except runner are protected by Supervisor.mu.
+type supervisedChild struct {
+	runner Runner
+	status ChildStatus
+
+	gen     int
+	running bool
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+// childExit is sent by a child goroutine when its runner returns
+type childExit struct {
+	idx int
+	gen int
+	err error
+}
+
+// NewSupervisor creates a new Supervisor. Use Add to register children
+// before calling Run.
+func NewSupervisor(name string, options ...SupervisorOption) *Supervisor {
+	opts := &SupervisorOptions{
+		Strategy:      OneForOne,
+		MaxRestarts:   5,
+		RestartWindow: time.Minute,
+		Backoff:       ExponentialBackoff(100*time.Millisecond, 10*time.Second),
+	}
+	for _, o := range options {
+		o(opts)
+	}
+	return &Supervisor{Name: name, opts: opts}
+}
+
+// Add registers a child runner. It must be called before Run.
+func (s *Supervisor) Add(name string, r Runner) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.children = append(s.children, &supervisedChild{
+		runner: r,
+		status: ChildStatus{Name: name, State: ChildStopped},
+	})
+}
+
+// Status returns the status of every child, in the order they were added.
+func (s *Supervisor) Status() []ChildStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result := make([]ChildStatus, len(s.children))
+	for i, c := range s.children {
+		result[i] = c.status
+	}
+	return result
+}
+
+// Degraded returns true if any child is restarting or has failed.
+// This is meant to be reported by health endpoints.
+func (s *Supervisor) Degraded() bool {
+	for _, c := range s.Status() {
+		if c.State == ChildRestarting || c.State == ChildFailed {
+			return true
+		}
+	}
+	return false
+}
+
+// Run implements the Runner interface. It blocks until the context is
+// canceled, every child has finished or the restart budget is exhausted.
+func (s *Supervisor) Run(ctx context.Context) error {
+	exits := make(chan childExit)
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	defer func() {
+		close(stop)
+		wg.Wait()
+	}()
+
+	start := func(idx int) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		c := s.children[idx]
+		childCtx, cancel := context.WithCancel(ctx)
+		c.gen++
+		c.running = true
+		c.cancel = cancel
+		c.done = make(chan struct{})
+		c.status.State = ChildRunning
+		c.status.StartedAt = time.Now()
+
+		gen, done, r, name := c.gen, c.done, c.runner, c.status.Name
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx2 := trace.StartSpan(childCtx, name)
+			err := r.Run(ctx2)
+			trace.End(ctx2)
+			close(done)
+			select {
+			case exits <- childExit{idx: idx, gen: gen, err: err}:
+			case <-stop:
+			}
+		}()
+	}
+
+	s.mu.Lock()
+	n := len(s.children)
+	s.mu.Unlock()
+	for i := range n {
+		start(i)
+	}
+
+	var restarts []time.Time
+	for {
+		var e childExit
+		select {
+		case <-ctx.Done():
+			return s.shutdown(ctx, nil)
+		case e = <-exits:
+		}
+
+		s.mu.Lock()
+		c := s.children[e.idx]
+		if e.gen != c.gen || !c.running {
+			// stale exit of a child we stopped ourselves
+			s.mu.Unlock()
+			continue
+		}
+		c.running = false
+		// the child returned, release its context so that it does not
+		// stay registered under ctx until the supervisor stops
+		c.cancel()
+		c.status.LastError = e.err
+		if e.err == nil || ctx.Err() != nil {
+			c.status.State = ChildStopped
+			allDone := !s.anyRunning()
+			s.mu.Unlock()
+			if allDone {
+				return s.shutdown(ctx, nil)
+			}
+			continue
+		}
+		c.status.State = ChildRestarting
+		name := c.status.Name
+		s.mu.Unlock()
+
+		log.Error(ctx, s.Name, log.F{"child": name}, events.NewErrorInfo(e.err))
+
+		now := time.Now()
+		restarts = append(restarts, now)
+		for len(restarts) > 0 && now.Sub(restarts[0]) > s.opts.RestartWindow {
+			restarts = restarts[1:]
+		}
+		if len(restarts) > s.opts.MaxRestarts {
+			s.mu.Lock()
+			c.status.State = ChildFailed
+			s.mu.Unlock()
+			return s.shutdown(ctx, fmt.Errorf("supervisor %s exceeded %d restarts in %s: %w",
+				s.Name, s.opts.MaxRestarts, s.opts.RestartWindow, e.err))
+		}
+
+		affected := s.affected(e.idx, n)
+		for i := len(affected) - 1; i >= 0; i-- {
+			s.stopChild(affected[i], ChildRestarting)
+		}
+
+		Sleep(ctx, s.opts.Backoff.Next(len(restarts)))
+		if ctx.Err() != nil {
+			return s.shutdown(ctx, nil)
+		}
+
+		log.Info(ctx, "async.supervisor restarting", log.F{
+			"supervisor": s.Name,
+			"child":      name,
+			"restarts":   len(restarts),
+		})
+		for _, idx := range affected {
+			s.mu.Lock()
+			s.children[idx].status.Restarts++
+			s.mu.Unlock()
+			start(idx)
+		}
+	}
+}
+
+// affected returns the indexes of the children to restart when the
+// child at idx fails, in start order.
+func (s *Supervisor) affected(idx, n int) []int {
+	var result []int
+	for i := range n {
+		switch s.opts.Strategy {
+		case OneForAll:
+			result = append(result, i)
+		case RestForOne:
+			if i >= idx {
+				result = append(result, i)
+			}
+		default:
+			if i == idx {
+				result = append(result, i)
+			}
+		}
+	}
+	return result
+}
+
+// anyRunning returns true if any child is running. s.mu must be held.
+func (s *Supervisor) anyRunning() bool {
+	for _, c := range s.children {
+		if c.running {
+			return true
+		}
+	}
+	return false
+}
+
+// stopChild cancels the child at idx and waits for it to return
+func (s *Supervisor) stopChild(idx int, state ChildState) {
+	s.mu.Lock()
+	c := s.children[idx]
+	wasRunning := c.running
+	c.running = false
+	if c.status.State != ChildFailed {
+		c.status.State = state
+	}
+	cancel, done := c.cancel, c.done
+	s.mu.Unlock()
+
+	if wasRunning {
+		cancel()
+		<-done
+	}
+}
+
+// shutdown stops all children in the reverse order they were added and
+// closes them. The returned error joins err with any close errors.
+func (s *Supervisor) shutdown(ctx context.Context, err error) error {
+	s.mu.Lock()
+	n := len(s.children)
+	s.mu.Unlock()
+
+	errs := []error{err}
+	closeCtx := context.WithoutCancel(ctx)
+	for i := n - 1; i >= 0; i-- {
+		s.stopChild(i, ChildStopped)
+		if cerr := RunClose(closeCtx, s.children[i].runner); cerr != nil {
+			log.Error(ctx, "Error when closing:", events.NewErrorInfo(cerr))
+			errs = append(errs, cerr)
+		}
+	}
+	return errors.Join(errs...)
+}
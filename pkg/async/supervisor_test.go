@@ -0,0 +1,166 @@
+//go:build !or_e2e
+
+package async_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/getoutreach/gobox/pkg/async"
+	"gotest.tools/v3/assert"
+)
+
+// flakyRunner fails the first `failures` runs and then blocks until
+// the context is canceled.
+type flakyRunner struct {
+	failures int32
+	runs     atomic.Int32
+	closed   atomic.Bool
+}
+
+func (f *flakyRunner) Run(ctx context.Context) error {
+	if f.runs.Add(1) <= f.failures {
+		return errors.New("flaky")
+	}
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (f *flakyRunner) Close(context.Context) error {
+	f.closed.Store(true)
+	return nil
+}
+
+func fastRestarts() async.SupervisorOption {
+	return async.WithRestartBackoff(async.ConstantBackoff(time.Millisecond))
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for condition")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func runSupervisor(ctx context.Context, sup *async.Supervisor) chan error {
+	result := make(chan error, 1)
+	go func() {
+		result <- sup.Run(ctx)
+	}()
+	return result
+}
+
+func TestSupervisorOneForOne(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	flaky, stable := &flakyRunner{failures: 2}, &flakyRunner{}
+	sup := async.NewSupervisor("test", fastRestarts())
+	sup.Add("flaky", flaky)
+	sup.Add("stable", stable)
+	result := runSupervisor(ctx, sup)
+
+	waitFor(t, func() bool { return flaky.runs.Load() == 3 })
+	waitFor(t, func() bool { return !sup.Degraded() })
+	assert.Equal(t, stable.runs.Load(), int32(1))
+
+	status := sup.Status()
+	assert.Equal(t, status[0].Name, "flaky")
+	assert.Equal(t, status[0].Restarts, 2)
+	assert.Equal(t, status[0].State, async.ChildRunning)
+	assert.Equal(t, status[1].Restarts, 0)
+
+	cancel()
+	assert.NilError(t, <-result)
+	assert.Assert(t, flaky.closed.Load())
+	assert.Assert(t, stable.closed.Load())
+	assert.Equal(t, sup.Status()[0].State, async.ChildStopped)
+}
+
+func TestSupervisorReleasesFailedChildContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	var first atomic.Pointer[context.Context]
+	var runs atomic.Int32
+	sup := async.NewSupervisor("test", fastRestarts())
+	sup.Add("child", async.Func(func(ctx context.Context) error {
+		if runs.Add(1) == 1 {
+			first.Store(&ctx)
+			return errors.New("failed")
+		}
+		<-ctx.Done()
+		return ctx.Err()
+	}))
+	result := runSupervisor(ctx, sup)
+
+	// the context of the failed run is canceled while the supervisor
+	// keeps running the restarted child
+	waitFor(t, func() bool { return runs.Load() == 2 })
+	assert.ErrorIs(t, (*first.Load()).Err(), context.Canceled)
+
+	cancel()
+	assert.NilError(t, <-result)
+}
+
+func TestSupervisorOneForAll(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	first, flaky := &flakyRunner{}, &flakyRunner{failures: 1}
+	sup := async.NewSupervisor("test", fastRestarts(), async.WithStrategy(async.OneForAll))
+	sup.Add("first", first)
+	sup.Add("flaky", flaky)
+	result := runSupervisor(ctx, sup)
+
+	waitFor(t, func() bool { return flaky.runs.Load() == 2 && first.runs.Load() == 2 })
+
+	cancel()
+	assert.NilError(t, <-result)
+}
+
+func TestSupervisorRestForOne(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	first, flaky, last := &flakyRunner{}, &flakyRunner{failures: 1}, &flakyRunner{}
+	sup := async.NewSupervisor("test", fastRestarts(), async.WithStrategy(async.RestForOne))
+	sup.Add("first", first)
+	sup.Add("flaky", flaky)
+	sup.Add("last", last)
+	result := runSupervisor(ctx, sup)
+
+	waitFor(t, func() bool { return flaky.runs.Load() == 2 && last.runs.Load() == 2 })
+	assert.Equal(t, first.runs.Load(), int32(1))
+
+	cancel()
+	assert.NilError(t, <-result)
+}
+
+func TestSupervisorRestartBudget(t *testing.T) {
+	flaky := &flakyRunner{failures: 100}
+	sup := async.NewSupervisor("test", fastRestarts(), async.WithRestartBudget(3, time.Minute))
+	sup.Add("flaky", flaky)
+
+	err := sup.Run(t.Context())
+	assert.ErrorContains(t, err, "flaky")
+	assert.Equal(t, flaky.runs.Load(), int32(4))
+	assert.Equal(t, sup.Status()[0].State, async.ChildFailed)
+	assert.Assert(t, sup.Degraded())
+	assert.Assert(t, flaky.closed.Load())
+}
+
+func TestSupervisorChildrenDone(t *testing.T) {
+	sup := async.NewSupervisor("test")
+	sup.Add("done", async.Func(func(context.Context) error { return nil }))
+
+	assert.NilError(t, sup.Run(t.Context()))
+	assert.Equal(t, sup.Status()[0].State, async.ChildStopped)
+}
@@ -0,0 +1,200 @@
+// Copyright 2026 Outreach Corporation. All Rights Reserved.
+
+// Description: Provides context-aware synchronization primitives
+
+package async
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"golang.org/x/sync/semaphore"
+)
+
+// waitSeconds registers the async_wait_seconds metric for reporting how
+// long callers wait on synchronization primitives, in seconds.
+var waitSeconds = promauto.NewHistogramVec( // nolint:gochecknoglobals // Why: cleaner to use everywhere
+	prometheus.HistogramOpts{
+		Name:    "async_wait_seconds",
+		Help:    "The time spent waiting on an async synchronization primitive, in seconds",
+		Buckets: prometheus.DefBuckets,
+	},
+	[]string{"name", "primitive", "acquired"}, // Labels
+)
+
+// SyncOption configures the context-aware synchronization primitives
+// (MutexWithContext, RWMutex, Semaphore and Cond) and the rate limiters.
+type SyncOption func(*syncOptions)
+
+type syncOptions struct {
+	// name is the value of the "name" label of the wait metrics, wait
+	// metrics are only reported when it is set.
+	name string
+}
+
+// WithWaitMetrics reports the time spent waiting on the primitive in
+// the async_wait_seconds histogram, labeled with the provided name.
+func WithWaitMetrics(name string) SyncOption {
+	return func(opts *syncOptions) {
+		opts.name = name
+	}
+}
+
+func newSyncOptions(options []SyncOption) syncOptions {
+	var opts syncOptions
+	for _, o := range options {
+		o(&opts)
+	}
+	return opts
+}
+
+// observe reports the time waited since start, if metrics are enabled
+func (o *syncOptions) observe(primitive string, start time.Time, err error) {
+	if o.name == "" {
+		return
+	}
+	waitSeconds.WithLabelValues(o.name, primitive, strconv.FormatBool(err == nil)).
+		Observe(time.Since(start).Seconds())
+}
+
+// rwMutexMaxReaders is the maximum number of concurrent readers of a
+// RWMutex. A writer acquires all of them.
+const rwMutexMaxReaders = 1 << 30
+
+// RWMutex is a reader/writer lock that supports context cancellation.
+//
+// Waiters are served in FIFO order, so a waiting writer blocks new
+// readers and cannot be starved. As with MutexWithContext, locking can
+// fail and the returned error must be checked.
+type RWMutex struct {
+	sem  *semaphore.Weighted
+	opts syncOptions
+}
+
+// NewRWMutex creates a new RWMutex instance.
+func NewRWMutex(options ...SyncOption) *RWMutex {
+	return &RWMutex{sem: semaphore.NewWeighted(rwMutexMaxReaders), opts: newSyncOptions(options)}
+}
+
+// Lock acquires the mutex for writing, blocking until it is available
+// or the context is done.
+func (m *RWMutex) Lock(ctx context.Context) error {
+	start := time.Now()
+	err := m.sem.Acquire(ctx, rwMutexMaxReaders)
+	m.opts.observe("rwmutex.write", start, err)
+	return err
+}
+
+// Unlock releases the write lock.
+func (m *RWMutex) Unlock() {
+	m.sem.Release(rwMutexMaxReaders)
+}
+
+// RLock acquires the mutex for reading, blocking until it is available
+// or the context is done.
+func (m *RWMutex) RLock(ctx context.Context) error {
+	start := time.Now()
+	err := m.sem.Acquire(ctx, 1)
+	m.opts.observe("rwmutex.read", start, err)
+	return err
+}
+
+// RUnlock releases a read lock.
+func (m *RWMutex) RUnlock() {
+	m.sem.Release(1)
+}
+
+// Semaphore is a weighted semaphore that supports context cancellation.
+type Semaphore struct {
+	sem  *semaphore.Weighted
+	opts syncOptions
+}
+
+// NewSemaphore creates a new Semaphore with the given maximum combined
+// weight for concurrent access.
+func NewSemaphore(size int64, options ...SyncOption) *Semaphore {
+	return &Semaphore{sem: semaphore.NewWeighted(size), opts: newSyncOptions(options)}
+}
+
+// Acquire acquires the semaphore with a weight of n, blocking until
+// resources are available or the context is done. On failure, no
+// resources are acquired.
+func (s *Semaphore) Acquire(ctx context.Context, n int64) error {
+	start := time.Now()
+	err := s.sem.Acquire(ctx, n)
+	s.opts.observe("semaphore", start, err)
+	return err
+}
+
+// TryAcquire acquires the semaphore with a weight of n without
+// blocking. It returns false, leaving the semaphore unchanged, if the
+// resources are not available.
+func (s *Semaphore) TryAcquire(n int64) bool {
+	return s.sem.TryAcquire(n)
+}
+
+// Release releases the semaphore with a weight of n.
+func (s *Semaphore) Release(n int64) {
+	s.sem.Release(n)
+}
+
+// Cond is a condition variable whose Wait supports context cancellation.
+//
+// Unlike sync.Cond, it only supports Broadcast: every waiter is woken
+// up and is expected to re-check its condition.
+//
+//	mu.Lock()
+//	for !ready() {
+//	    if err := cond.Wait(ctx); err != nil {
+//	        mu.Unlock()
+//	        return err
+//	    }
+//	}
+//	mu.Unlock()
+type Cond struct {
+	// L is held while observing or changing the condition
+	L sync.Locker
+
+	mu   sync.Mutex
+	ch   chan struct{}
+	opts syncOptions
+}
+
+// NewCond creates a new Cond using l as its Locker.
+func NewCond(l sync.Locker, options ...SyncOption) *Cond {
+	return &Cond{L: l, ch: make(chan struct{}), opts: newSyncOptions(options)}
+}
+
+// Wait atomically unlocks c.L and suspends the caller until Broadcast
+// is called or the context is done. c.L is locked again before Wait
+// returns, in both cases. The context error is returned if the wait
+// ended because of the context.
+func (c *Cond) Wait(ctx context.Context) error {
+	c.mu.Lock()
+	ch := c.ch
+	c.mu.Unlock()
+
+	start := time.Now()
+	c.L.Unlock()
+	var err error
+	select {
+	case <-ch:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	c.L.Lock()
+	c.opts.observe("cond", start, err)
+	return err
+}
+
+// Broadcast wakes all goroutines waiting on c.
+func (c *Cond) Broadcast() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	close(c.ch)
+	c.ch = make(chan struct{})
+}
@@ -0,0 +1,129 @@
+//go:build !or_e2e
+
+package async_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/getoutreach/gobox/pkg/async"
+	"github.com/prometheus/client_golang/prometheus"
+	"gotest.tools/v3/assert"
+)
+
+func TestRWMutexReadersShare(t *testing.T) {
+	mu := async.NewRWMutex()
+	assert.NilError(t, mu.RLock(t.Context()))
+	assert.NilError(t, mu.RLock(t.Context()))
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, mu.Lock(ctx), context.DeadlineExceeded)
+
+	mu.RUnlock()
+	mu.RUnlock()
+	assert.NilError(t, mu.Lock(t.Context()))
+	mu.Unlock()
+}
+
+func TestRWMutexWriterExcludesReaders(t *testing.T) {
+	mu := async.NewRWMutex()
+	assert.NilError(t, mu.Lock(t.Context()))
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, mu.RLock(ctx), context.DeadlineExceeded)
+
+	mu.Unlock()
+	assert.NilError(t, mu.RLock(t.Context()))
+	mu.RUnlock()
+}
+
+func TestSemaphore(t *testing.T) {
+	sem := async.NewSemaphore(3)
+	assert.Assert(t, sem.TryAcquire(2))
+	assert.Assert(t, !sem.TryAcquire(2))
+	assert.Assert(t, sem.TryAcquire(1))
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, sem.Acquire(ctx, 1), context.DeadlineExceeded)
+
+	sem.Release(3)
+	assert.NilError(t, sem.Acquire(t.Context(), 3))
+}
+
+func TestCondBroadcast(t *testing.T) {
+	var mu sync.Mutex
+	cond := async.NewCond(&mu)
+	ready := false
+
+	var wg sync.WaitGroup
+	for range 3 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			mu.Lock()
+			defer mu.Unlock()
+			for !ready {
+				assert.Check(t, cond.Wait(t.Context()))
+			}
+		}()
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	mu.Lock()
+	ready = true
+	cond.Broadcast()
+	mu.Unlock()
+	wg.Wait()
+}
+
+func TestCondWaitCanceled(t *testing.T) {
+	var mu sync.Mutex
+	cond := async.NewCond(&mu)
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+	defer cancel()
+
+	mu.Lock()
+	assert.ErrorIs(t, cond.Wait(ctx), context.DeadlineExceeded)
+	// the lock must be held again after Wait returns
+	assert.Assert(t, !mu.TryLock())
+	mu.Unlock()
+}
+
+func TestWaitMetrics(t *testing.T) {
+	// unique name so repeated runs (-count) don't accumulate
+	name := fmt.Sprintf("TestWaitMetrics-%d", time.Now().UnixNano())
+	mu := async.NewMutexWithContext(async.WithWaitMetrics(name))
+	assert.NilError(t, mu.Lock(t.Context()))
+
+	ctx, cancel := context.WithTimeout(t.Context(), time.Millisecond)
+	defer cancel()
+	assert.Assert(t, mu.Lock(ctx) != nil)
+	mu.Unlock()
+
+	families, err := prometheus.DefaultGatherer.Gather()
+	assert.NilError(t, err)
+
+	acquired := map[string]uint64{}
+	for _, family := range families {
+		if family.GetName() != "async_wait_seconds" {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, l := range m.GetLabel() {
+				labels[l.GetName()] = l.GetValue()
+			}
+			if labels["name"] == name {
+				acquired[labels["acquired"]] = m.GetHistogram().GetSampleCount()
+			}
+		}
+	}
+	assert.DeepEqual(t, acquired, map[string]uint64{"true": 1, "false": 1})
+}
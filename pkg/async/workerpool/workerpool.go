@@ -0,0 +1,532 @@
+// Copyright 2026 Outreach Corporation. All Rights Reserved.
+
+// Description: Provides a generic worker pool with results and metrics
+
+// Package workerpool implements a generic, dynamically sized worker
+// pool which traces and logs every task, collects their results and
+// reports its activity in metrics.
+//
+// It replaces the deprecated async/pool package:
+//
+//	p := workerpool.New[*User](ctx,
+//	    workerpool.WithName("fetch-users"),
+//	    workerpool.WithSize(workerpool.ConstantSize(10)),
+//	)
+//	for _, id := range ids {
+//	    p.Go(func(ctx context.Context) (*User, error) {
+//	        return fetchUser(ctx, id)
+//	    })
+//	}
+//	users, err := p.Wait()
+package workerpool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/getoutreach/gobox/pkg/async"
+	"github.com/getoutreach/gobox/pkg/events"
+	"github.com/getoutreach/gobox/pkg/log"
+	"github.com/getoutreach/gobox/pkg/orerr"
+	"github.com/getoutreach/gobox/pkg/trace"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// queueDepth registers the async_workerpool_queue_depth metric for
+// reporting the number of tasks waiting for a worker.
+var queueDepth = promauto.NewGaugeVec( // nolint:gochecknoglobals // Why: cleaner to use everywhere
+	prometheus.GaugeOpts{
+		Name: "async_workerpool_queue_depth",
+		Help: "The number of tasks waiting for a worker",
+	},
+	[]string{"name"}, // Labels
+)
+
+// workers registers the async_workerpool_workers metric for reporting
+// the current size of the pools.
+var workers = promauto.NewGaugeVec( // nolint:gochecknoglobals // Why: cleaner to use everywhere
+	prometheus.GaugeOpts{
+		Name: "async_workerpool_workers",
+		Help: "The number of workers in the pool",
+	},
+	[]string{"name"}, // Labels
+)
+
+// activeWorkers registers the async_workerpool_active_workers metric
+// for reporting the number of workers running a task.
+var activeWorkers = promauto.NewGaugeVec( // nolint:gochecknoglobals // Why: cleaner to use everywhere
+	prometheus.GaugeOpts{
+		Name: "async_workerpool_active_workers",
+		Help: "The number of workers running a task",
+	},
+	[]string{"name"}, // Labels
+)
+
+// taskSeconds registers the async_workerpool_task_seconds metric for
+// reporting how long tasks take to run, in seconds.
+var taskSeconds = promauto.NewHistogramVec( // nolint:gochecknoglobals // Why: cleaner to use everywhere
+	prometheus.HistogramOpts{
+		Name:    "async_workerpool_task_seconds",
+		Help:    "The time spent running a task, in seconds",
+		Buckets: prometheus.DefBuckets,
+	},
+	[]string{"name", "success"}, // Labels
+)
+
+// SizeFunc tells the pool how many workers it should have
+type SizeFunc func() int
+
+// ConstantSize provides a constant size for the pool
+func ConstantSize(size int) SizeFunc {
+	return func() int {
+		return size
+	}
+}
+
+// FullBehavior defines what Go does when all workers are busy and the
+// queue is full
+type FullBehavior int
+
+const (
+	// WaitWhenFull blocks until the task can be queued
+	WaitWhenFull FullBehavior = iota
+
+	// RejectWhenFull fails right away with an orerr.LimitExceededError
+	RejectWhenFull
+)
+
+// ResultOrder defines the order in which results are streamed
+type ResultOrder int
+
+const (
+	// Unordered streams results as soon as tasks finish
+	Unordered ResultOrder = iota
+
+	// Ordered streams results in the order the tasks were submitted
+	Ordered
+)
+
+// Options configures a Pool
+type Options struct {
+	// Name of the pool, used for spans, logs and metrics
+	Name string
+
+	// Size dynamically resolves the number of workers. Defaults to
+	// runtime.GOMAXPROCS.
+	Size SizeFunc
+
+	// ResizeEvery is the interval at which Size is called to grow or
+	// shrink the pool. Zero or less disables resizing: Size is only
+	// called once, when the pool is created.
+	ResizeEvery time.Duration
+
+	// BufferLength is the number of tasks which can be queued while all
+	// workers are busy
+	BufferLength int
+
+	// WhenFull defines what Go does when the queue is full. Defaults to
+	// WaitWhenFull.
+	WhenFull FullBehavior
+
+	// Stream sends results to the channel returned by Results instead of
+	// collecting them for Wait
+	Stream bool
+
+	// Order is the order of the streamed results
+	Order ResultOrder
+}
+
+// Option configures a Pool
+type Option func(*Options)
+
+// WithName sets the name of the pool
+func WithName(name string) Option {
+	return func(opts *Options) {
+		opts.Name = name
+	}
+}
+
+// WithSize sets the function resolving the number of workers
+func WithSize(size SizeFunc) Option {
+	return func(opts *Options) {
+		opts.Size = size
+	}
+}
+
+// WithResizeEvery sets how often the pool is resized, zero or less
+// disables resizing
+func WithResizeEvery(d time.Duration) Option {
+	return func(opts *Options) {
+		opts.ResizeEvery = d
+	}
+}
+
+// WithBufferLength sets the number of tasks which can be queued while
+// all workers are busy
+func WithBufferLength(n int) Option {
+	return func(opts *Options) {
+		opts.BufferLength = n
+	}
+}
+
+// WithFullBehavior sets what Go does when the queue is full
+func WithFullBehavior(b FullBehavior) Option {
+	return func(opts *Options) {
+		opts.WhenFull = b
+	}
+}
+
+// WithResultStream streams the results to the channel returned by
+// Results, in the given order, instead of collecting them for Wait
+func WithResultStream(order ResultOrder) Option {
+	return func(opts *Options) {
+		opts.Stream = true
+		opts.Order = order
+	}
+}
+
+// Result is the outcome of a task
+type Result[T any] struct {
+	// Index is the position of the task in submission order
+	Index int
+
+	Value T
+	Err   error
+}
+
+// TaskError is the error of a single task, as returned by Wait
+type TaskError struct {
+	// Index is the position of the task in submission order
+	Index int
+
+	Err error
+}
+
+// Error implements the error interface
+func (e *TaskError) Error() string {
+	return fmt.Sprintf("task %d: %v", e.Index, e.Err)
+}
+
+// Unwrap returns the error of the task
+func (e *TaskError) Unwrap() error {
+	return e.Err
+}
+
+// task is a function waiting in the queue
+type task[T any] struct {
+	index int
+	fn    func(ctx context.Context) (T, error)
+}
+
+// Pool runs functions returning a T on a dynamically sized set of
+// workers.
+//
+// Every task runs in its own span named after the pool, and errors are
+// logged like async.Run does. Panics are recovered and reported as an
+// *async.PanicError for the task. Results are either collected and
+// returned by Wait, or streamed through Results when the pool is
+// created with WithResultStream.
+type Pool[T any] struct {
+	ctx    context.Context
+	cancel func(error)
+	opts   *Options
+	queue  chan task[T]
+
+	// sendMu serializes queueing tasks, queued is the number of tasks
+	// queued so far
+	sendMu sync.Mutex
+	queued int
+
+	// mu protects the fields below
+	mu         sync.Mutex
+	closed     bool
+	results    map[int]T
+	errs       []error
+	submitting sync.WaitGroup
+
+	// emitMu serializes sending results to the stream
+	emitMu  sync.Mutex
+	stream  chan Result[T]
+	pending map[int]Result[T]
+	next    int
+
+	stopResize chan struct{}
+	resizeDone chan struct{}
+	wg         sync.WaitGroup
+	waitOnce   sync.Once
+}
+
+// New creates a Pool and starts its workers. Wait or Close must be
+// called to release its resources.
+//
+// Tasks run with a context derived from ctx.
+func New[T any](ctx context.Context, options ...Option) *Pool[T] {
+	opts := &Options{
+		Name:        "async.workerpool",
+		Size:        ConstantSize(runtime.GOMAXPROCS(0)),
+		ResizeEvery: time.Minute,
+	}
+	for _, o := range options {
+		o(opts)
+	}
+
+	ctx, cancel := orerr.CancelWithError(ctx)
+	p := &Pool[T]{
+		ctx:        ctx,
+		cancel:     cancel,
+		opts:       opts,
+		queue:      make(chan task[T], opts.BufferLength),
+		results:    map[int]T{},
+		pending:    map[int]Result[T]{},
+		stopResize: make(chan struct{}),
+		resizeDone: make(chan struct{}),
+	}
+	if opts.Stream {
+		p.stream = make(chan Result[T], opts.BufferLength)
+	}
+
+	// spawn initial workers synchronously
+	cancellations := p.spawnWorkers(opts.Size())
+	go p.resize(cancellations)
+	return p
+}
+
+// Go submits fn to the pool. It returns an error if the task was not
+// queued: an *orerr.ShutdownError once Wait or Close was called, an
+// orerr.LimitExceededError if the queue is full with RejectWhenFull, or
+// the error of the pool context once it is done.
+func (p *Pool[T]) Go(fn func(ctx context.Context) (T, error)) error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return &orerr.ShutdownError{Err: errors.New("workerpool: pool is closed")}
+	}
+	p.submitting.Add(1)
+	defer p.submitting.Done()
+	p.mu.Unlock()
+
+	// tasks are queued one at a time so that their indexes follow the
+	// queue order, without holes left by rejected tasks
+	p.sendMu.Lock()
+	defer p.sendMu.Unlock()
+	t := task[T]{index: p.queued, fn: fn}
+
+	switch p.opts.WhenFull {
+	case RejectWhenFull:
+		select {
+		case p.queue <- t:
+		default:
+			return orerr.LimitExceededError{Kind: "PoolQueue"}
+		}
+	case WaitWhenFull:
+		select {
+		case p.queue <- t:
+		case <-p.ctx.Done():
+			return p.ctx.Err()
+		}
+	}
+	queueDepth.WithLabelValues(p.opts.Name).Inc()
+	p.queued++
+	return nil
+}
+
+// Results returns the channel results are streamed to, or nil if the
+// pool was not created with WithResultStream. The channel is closed once
+// Wait returns, so it must be consumed concurrently with Wait.
+func (p *Pool[T]) Results() <-chan Result[T] {
+	return p.stream
+}
+
+// Wait stops accepting new tasks and waits for the queued ones to
+// finish. It returns the values of the tasks in submission order (nil
+// when results are streamed) and the errors of the failed tasks, each
+// wrapped in a *TaskError, joined with errors.Join.
+func (p *Pool[T]) Wait() ([]T, error) {
+	p.waitOnce.Do(p.shutdown)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var values []T
+	if !p.opts.Stream {
+		values = make([]T, p.queued)
+		for i, v := range p.results {
+			values[i] = v
+		}
+	}
+	return values, errors.Join(p.errs...)
+}
+
+// Close cancels the context of the running tasks, fails the queued ones
+// with an *orerr.ShutdownError and waits for the workers to terminate.
+func (p *Pool[T]) Close() {
+	p.cancel(&orerr.ShutdownError{Err: context.Canceled})
+	p.waitOnce.Do(p.shutdown)
+}
+
+// shutdown closes the queue and waits for the workers to drain it
+func (p *Pool[T]) shutdown() {
+	p.mu.Lock()
+	p.closed = true
+	p.mu.Unlock()
+
+	// there is no sender left once submitting is done
+	p.submitting.Wait()
+	close(p.queue)
+
+	// stop resizing so that no worker is added while waiting for them
+	close(p.stopResize)
+	<-p.resizeDone
+	p.wg.Wait()
+
+	p.cancel(&orerr.ShutdownError{Err: context.Canceled})
+	if p.stream != nil {
+		close(p.stream)
+	}
+}
+
+// spawnWorkers starts n workers and returns their cancellations
+func (p *Pool[T]) spawnWorkers(n int) []context.CancelFunc {
+	cancels := make([]context.CancelFunc, 0, n)
+	for range n {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancels = append(cancels, cancel)
+		p.wg.Add(1)
+		go p.worker(ctx)
+	}
+	workers.WithLabelValues(p.opts.Name).Add(float64(n))
+	return cancels
+}
+
+// resize adjusts the number of workers every ResizeEvery
+func (p *Pool[T]) resize(cancellations []context.CancelFunc) {
+	defer close(p.resizeDone)
+
+	if p.opts.ResizeEvery <= 0 {
+		<-p.stopResize
+		return
+	}
+
+	ticker := time.NewTicker(p.opts.ResizeEvery)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-p.stopResize:
+			return
+		}
+
+		prevSize := len(cancellations)
+		size := max(p.opts.Size(), 0)
+		switch {
+		case size < prevSize:
+			for _, cancel := range cancellations[size:] {
+				cancel()
+			}
+			cancellations = cancellations[:size]
+		case size > prevSize:
+			cancellations = append(cancellations, p.spawnWorkers(size-prevSize)...)
+		default:
+			continue
+		}
+
+		log.Info(p.ctx, "async.pool resized", log.F{
+			"pool":     p.opts.Name,
+			"size":     size,
+			"previous": prevSize,
+		})
+	}
+}
+
+// worker runs queued tasks until the queue is closed or the worker is
+// canceled by a resize
+func (p *Pool[T]) worker(ctx context.Context) {
+	defer p.wg.Done()
+	defer workers.WithLabelValues(p.opts.Name).Dec()
+	for {
+		// prefer exiting when canceled, even if tasks are queued
+		if ctx.Err() != nil {
+			return
+		}
+
+		select {
+		case t, ok := <-p.queue:
+			if !ok {
+				return
+			}
+			queueDepth.WithLabelValues(p.opts.Name).Dec()
+			p.record(p.run(t))
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// run runs a single task in its own span
+func (p *Pool[T]) run(t task[T]) (res Result[T]) {
+	res.Index = t.index
+	if err := p.ctx.Err(); err != nil {
+		res.Err = p.ctx.Err()
+		return res
+	}
+
+	ctx := trace.StartSpan(p.ctx, p.opts.Name)
+	defer trace.End(ctx)
+
+	activeWorkers.WithLabelValues(p.opts.Name).Inc()
+	defer activeWorkers.WithLabelValues(p.opts.Name).Dec()
+
+	start := time.Now()
+	defer func() {
+		if r := recover(); r != nil {
+			res.Err = &async.PanicError{Info: events.NewErrorInfoFromPanic(r)}
+		}
+		taskSeconds.WithLabelValues(p.opts.Name, strconv.FormatBool(res.Err == nil)).
+			Observe(time.Since(start).Seconds())
+		if res.Err != nil && !errors.Is(res.Err, context.Canceled) {
+			log.Error(ctx, p.opts.Name, events.NewErrorInfo(res.Err))
+		}
+	}()
+
+	res.Value, res.Err = t.fn(ctx)
+	return res
+}
+
+// record stores or streams the result of a task
+func (p *Pool[T]) record(res Result[T]) {
+	p.mu.Lock()
+	if res.Err != nil {
+		p.errs = append(p.errs, &TaskError{Index: res.Index, Err: res.Err})
+	}
+	if !p.opts.Stream {
+		p.results[res.Index] = res.Value
+	}
+	p.mu.Unlock()
+
+	if p.stream == nil {
+		return
+	}
+
+	p.emitMu.Lock()
+	defer p.emitMu.Unlock()
+	if p.opts.Order == Unordered {
+		p.stream <- res
+		return
+	}
+
+	p.pending[res.Index] = res
+	for {
+		next, ok := p.pending[p.next]
+		if !ok {
+			return
+		}
+		delete(p.pending, p.next)
+		p.next++
+		p.stream <- next
+	}
+}
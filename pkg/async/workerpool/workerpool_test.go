@@ -0,0 +1,209 @@
+//go:build !or_e2e
+
+package workerpool_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/getoutreach/gobox/pkg/async"
+	"github.com/getoutreach/gobox/pkg/async/workerpool"
+	"github.com/getoutreach/gobox/pkg/orerr"
+	"github.com/prometheus/client_golang/prometheus"
+	"gotest.tools/v3/assert"
+)
+
+func TestPoolCollectsResults(t *testing.T) {
+	p := workerpool.New[int](t.Context(), workerpool.WithSize(workerpool.ConstantSize(3)))
+	for i := range 10 {
+		assert.NilError(t, p.Go(func(context.Context) (int, error) {
+			time.Sleep(time.Duration(10-i) * time.Millisecond)
+			return i * i, nil
+		}))
+	}
+
+	values, err := p.Wait()
+	assert.NilError(t, err)
+	assert.DeepEqual(t, values, []int{0, 1, 4, 9, 16, 25, 36, 49, 64, 81})
+
+	err = p.Go(func(context.Context) (int, error) { return 0, nil })
+	var shutdownErr *orerr.ShutdownError
+	assert.Assert(t, errors.As(err, &shutdownErr))
+}
+
+func TestPoolCollectsErrors(t *testing.T) {
+	p := workerpool.New[int](t.Context(), workerpool.WithSize(workerpool.ConstantSize(2)))
+	failure := errors.New("failed")
+	assert.NilError(t, p.Go(func(context.Context) (int, error) { return 1, nil }))
+	assert.NilError(t, p.Go(func(context.Context) (int, error) { return 0, failure }))
+	assert.NilError(t, p.Go(func(context.Context) (int, error) { panic("boom") }))
+
+	values, err := p.Wait()
+	assert.DeepEqual(t, values, []int{1, 0, 0})
+	assert.ErrorIs(t, err, failure)
+
+	var taskErr *workerpool.TaskError
+	assert.Assert(t, errors.As(err, &taskErr))
+	var panicErr *async.PanicError
+	assert.Assert(t, errors.As(err, &panicErr))
+}
+
+func TestPoolOrderedStream(t *testing.T) {
+	p := workerpool.New[int](t.Context(),
+		workerpool.WithSize(workerpool.ConstantSize(4)),
+		workerpool.WithResultStream(workerpool.Ordered),
+	)
+	go func() {
+		for i := range 20 {
+			//nolint:errcheck // Why: the pool is open and waits when full
+			p.Go(func(context.Context) (int, error) {
+				time.Sleep(time.Duration(i%3) * time.Millisecond)
+				return i, nil
+			})
+		}
+		//nolint:errcheck // Why: results are checked through the stream
+		p.Wait()
+	}()
+
+	next := 0
+	for res := range p.Results() {
+		assert.NilError(t, res.Err)
+		assert.Equal(t, res.Index, next)
+		assert.Equal(t, res.Value, next)
+		next++
+	}
+	assert.Equal(t, next, 20)
+}
+
+func TestPoolUnorderedStream(t *testing.T) {
+	p := workerpool.New[int](t.Context(),
+		workerpool.WithSize(workerpool.ConstantSize(2)),
+		workerpool.WithResultStream(workerpool.Unordered),
+	)
+	release := make(chan struct{})
+	assert.NilError(t, p.Go(func(context.Context) (int, error) {
+		<-release
+		return 0, nil
+	}))
+	assert.NilError(t, p.Go(func(context.Context) (int, error) { return 1, nil }))
+
+	assert.Equal(t, (<-p.Results()).Value, 1, "the fast task is streamed first")
+	close(release)
+	assert.Equal(t, (<-p.Results()).Value, 0)
+
+	values, err := p.Wait()
+	assert.NilError(t, err)
+	assert.Assert(t, values == nil)
+}
+
+func TestPoolRejectWhenFull(t *testing.T) {
+	p := workerpool.New[int](t.Context(),
+		workerpool.WithSize(workerpool.ConstantSize(1)),
+		workerpool.WithBufferLength(1),
+		workerpool.WithFullBehavior(workerpool.RejectWhenFull),
+	)
+	defer p.Close()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	assert.NilError(t, p.Go(func(context.Context) (int, error) {
+		close(started)
+		<-release
+		return 0, nil
+	}))
+	<-started
+	assert.NilError(t, p.Go(func(context.Context) (int, error) { return 1, nil }))
+
+	err := p.Go(func(context.Context) (int, error) { return 2, nil })
+	var limitErr orerr.LimitExceededError
+	assert.Assert(t, errors.As(err, &limitErr))
+	close(release)
+}
+
+func TestPoolResizes(t *testing.T) {
+	var size atomic.Int32
+	size.Store(1)
+	p := workerpool.New[int](t.Context(),
+		workerpool.WithSize(func() int { return int(size.Load()) }),
+		workerpool.WithResizeEvery(time.Millisecond),
+	)
+	defer p.Close()
+
+	var active, maxActive atomic.Int32
+	size.Store(4)
+	time.Sleep(20 * time.Millisecond)
+	for range 8 {
+		assert.NilError(t, p.Go(func(context.Context) (int, error) {
+			n := active.Add(1)
+			defer active.Add(-1)
+			for {
+				m := maxActive.Load()
+				if n <= m || maxActive.CompareAndSwap(m, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			return 0, nil
+		}))
+	}
+	_, err := p.Wait()
+	assert.NilError(t, err)
+	assert.Equal(t, maxActive.Load(), int32(4))
+}
+
+func TestPoolClose(t *testing.T) {
+	p := workerpool.New[int](t.Context(), workerpool.WithSize(workerpool.ConstantSize(1)), workerpool.WithBufferLength(1))
+
+	started := make(chan struct{})
+	assert.NilError(t, p.Go(func(ctx context.Context) (int, error) {
+		close(started)
+		<-ctx.Done()
+		return 0, ctx.Err()
+	}))
+	<-started
+	assert.NilError(t, p.Go(func(context.Context) (int, error) { return 1, nil }))
+	p.Close()
+
+	_, err := p.Wait()
+	var shutdownErr *orerr.ShutdownError
+	assert.Assert(t, errors.As(err, &shutdownErr))
+}
+
+// workerGauge returns the value of the workers gauge of the named pool
+func workerGauge(t *testing.T, name string) float64 {
+	families, err := prometheus.DefaultGatherer.Gather()
+	assert.NilError(t, err)
+	for _, family := range families {
+		if family.GetName() != "async_workerpool_workers" {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			for _, l := range m.GetLabel() {
+				if l.GetName() == "name" && l.GetValue() == name {
+					return m.GetGauge().GetValue()
+				}
+			}
+		}
+	}
+	return 0
+}
+
+func TestPoolWorkersGauge(t *testing.T) {
+	// unique name so repeated runs (-count) don't accumulate
+	name := fmt.Sprintf("TestPoolWorkersGauge-%d", time.Now().UnixNano())
+	p := workerpool.New[int](t.Context(),
+		workerpool.WithName(name),
+		workerpool.WithSize(workerpool.ConstantSize(3)),
+		workerpool.WithResizeEvery(0),
+	)
+	assert.Equal(t, workerGauge(t, name), 3.0)
+
+	assert.NilError(t, p.Go(func(context.Context) (int, error) { return 1, nil }))
+	_, err := p.Wait()
+	assert.NilError(t, err)
+	assert.Equal(t, workerGauge(t, name), 0.0)
+}
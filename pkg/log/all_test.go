@@ -9,5 +9,5 @@ import (
 )
 
 func TestAll(t *testing.T) {
-	shuffler.Run(t, fatalSuite{}, withSuite{}, callerSuite{})
+	shuffler.Run(t, fatalSuite{}, withSuite{}, callerSuite{}, debugSuite{}, sinkSuite{}, sampleSuite{}, redactSuite{}, fieldSuite{})
 }
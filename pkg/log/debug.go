@@ -0,0 +1,68 @@
+// Copyright 2026 Outreach Corporation. All Rights Reserved.
+
+// Description: Provides the buffers holding debug logs until an error
+
+package log
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/getoutreach/gobox/pkg/log/internal/entries"
+)
+
+// nolint:gochecknoglobals // Why: the limits apply to every debug buffer
+var (
+	dbgLimitsLock = sync.Mutex{}
+	dbgMaxItems   = entries.MaxItems
+	dbgMaxAge     = entries.MaxDuration
+)
+
+// debugBufferKey is the context key of the debug buffer of a request
+type debugBufferKey struct{}
+
+// SetDebugBufferLimits sets how many debug logs are held until an error
+// is logged, and how old they can be to be written out then. Defaults to
+// 200 logs and 2 minutes, which also replace non-positive limits.
+//
+// The limits apply to the global buffer and to the buffers created by
+// WithDebugBuffer from then on.
+// Note: this function should not be used in production code outside of service startup.
+func SetDebugBufferLimits(maxItems int, maxAge time.Duration) {
+	dbgLimitsLock.Lock()
+	defer dbgLimitsLock.Unlock()
+
+	dbgMaxItems, dbgMaxAge = maxItems, maxAge
+	dbgEntries.SetLimits(maxItems, maxAge)
+}
+
+// WithDebugBuffer returns a context holding its own buffer of debug logs,
+// as trace.StartCall does for every call. Debug logs with the context
+// are held in this buffer, and Error and Fatal with the context only
+// write out this buffer rather than the global one, so that an error in a
+// request only comes with the debug logs of that request.
+//
+// If the context already has a debug buffer, it is returned as is, so that
+// nested calls share the buffer of the request.
+func WithDebugBuffer(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(debugBufferKey{}).(*entries.Entries); ok {
+		return ctx
+	}
+
+	dbgLimitsLock.Lock()
+	buf := entries.NewWithLimits(dbgMaxItems, dbgMaxAge)
+	dbgLimitsLock.Unlock()
+	return context.WithValue(ctx, debugBufferKey{}, buf)
+}
+
+// debugEntries returns the debug buffer of the context, or the global
+// one if it has none
+func debugEntries(ctx context.Context) *entries.Entries {
+	if ctx != nil {
+		if buf, ok := ctx.Value(debugBufferKey{}).(*entries.Entries); ok {
+			return buf
+		}
+	}
+	return dbgEntries
+}
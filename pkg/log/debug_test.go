@@ -0,0 +1,74 @@
+//go:build !or_e2e
+
+package log_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/getoutreach/gobox/pkg/log"
+	"github.com/getoutreach/gobox/pkg/log/logtest"
+	"gotest.tools/v3/assert"
+)
+
+type debugSuite struct{}
+
+// messages returns the messages of the entries
+func messages(entries []log.F) []string {
+	var r []string
+	for _, entry := range entries {
+		r = append(r, entry["message"].(string))
+	}
+	return r
+}
+
+func (debugSuite) TestDebugBufferPerContext(t *testing.T) {
+	logs := logtest.NewLogRecorder(t)
+	defer logs.Close()
+	defer log.Purge(t.Context())
+
+	req1 := log.WithDebugBuffer(t.Context())
+	req2 := log.WithDebugBuffer(t.Context())
+	assert.Equal(t, log.WithDebugBuffer(req1), req1, "nested calls share the buffer")
+
+	log.Debug(t.Context(), "global debug")
+	log.Debug(req1, "req1 debug")
+	log.Debug(req2, "req2 debug")
+	log.Error(req1, "req1 error")
+	assert.DeepEqual(t, messages(logs.Entries()), []string{"req1 debug", "req1 error"})
+
+	log.Error(t.Context(), "global error")
+	assert.DeepEqual(t, messages(logs.Entries()), []string{"req1 debug", "req1 error", "global debug", "global error"})
+
+	log.Flush(req2)
+	assert.DeepEqual(t, messages(logs.Entries())[4:], []string{"req2 debug"})
+}
+
+func (debugSuite) TestDebugBufferLimits(t *testing.T) {
+	logs := logtest.NewLogRecorder(t)
+	defer logs.Close()
+	defer log.SetDebugBufferLimits(200, 2*time.Minute)
+
+	log.SetDebugBufferLimits(2, time.Minute)
+	ctx := log.WithDebugBuffer(t.Context())
+	for _, msg := range []string{"one", "two", "three"} {
+		log.Debug(ctx, msg)
+		log.Debug(t.Context(), msg)
+	}
+	log.Error(ctx, "error")
+	log.Error(t.Context(), "error")
+	assert.DeepEqual(t, messages(logs.Entries()), []string{"two", "three", "error", "two", "three", "error"})
+}
+
+func (debugSuite) TestDebugBufferNonPositiveLimits(t *testing.T) {
+	logs := logtest.NewLogRecorder(t)
+	defer logs.Close()
+	defer log.SetDebugBufferLimits(200, 2*time.Minute)
+
+	log.SetDebugBufferLimits(-1, 0)
+	ctx := log.WithDebugBuffer(t.Context())
+	log.Debug(ctx, "one")
+	log.Debug(ctx, "two")
+	log.Error(ctx, "error")
+	assert.DeepEqual(t, messages(logs.Entries()), []string{"one", "two", "error"})
+}
@@ -0,0 +1,206 @@
+// Copyright 2026 Outreach Corporation. All Rights Reserved.
+
+// Description: Provides the encoding of logs made of typed fields
+
+package log
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/getoutreach/gobox/internal/logf"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maxPooledEntrySize is the size of the largest entry whose encoder is
+// reused
+const maxPooledEntrySize = 64 * 1024
+
+// encodedField is a field of an entry being encoded, a Field or a value
+// added by a Marshaler when its kind is 0
+type encodedField struct {
+	Field
+	value any
+}
+
+// fieldEncoder encodes the entries made of Fields, see encodeFields
+type fieldEncoder struct {
+	fields []encodedField
+	buf    []byte
+}
+
+// nolint:gochecknoglobals // Why: reuses the buffers of the encoders
+var encoderPool = sync.Pool{New: func() any { return new(fieldEncoder) }}
+
+// onlyFields reports whether the marshalers are all Fields
+func onlyFields(mm Many) bool {
+	for _, m := range mm {
+		if _, ok := m.(Field); !ok && m != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// encodeFields formats an entry whose marshalers are all Fields as format
+// does, writing the JSON object directly rather than building an F and
+// encoding it with encoding/json. It returns false if the entry has a
+// value which encoding/json fails to encode.
+func encodeFields(ctx context.Context, msg, level string, ts time.Time, appInfo Marshaler, mm Many) (string, bool) {
+	e := encoderPool.Get().(*fieldEncoder)
+	defer func() {
+		// do not keep the buffers of unusually large entries
+		if cap(e.buf) <= maxPooledEntrySize {
+			e.fields = e.fields[:0]
+			e.buf = e.buf[:0]
+			encoderPool.Put(e)
+		}
+	}()
+
+	e.fields = append(e.fields,
+		encodedField{Field: String("message", msg)},
+		encodedField{Field: String("level", level)},
+		encodedField{Field: String("@timestamp", ts.Format(time.RFC3339Nano))},
+	)
+	appInfo.MarshalLog(e.add)
+	for _, m := range mm {
+		if f, ok := m.(Field); ok {
+			e.addField(f)
+		}
+	}
+	if span := trace.SpanFromContext(ctx); span != nil && span.SpanContext().TraceID().IsValid() {
+		e.addField(String("traceID", span.SpanContext().TraceID().String()))
+	}
+	addSource(e.add)
+
+	return e.encode()
+}
+
+// add adds a field set by a Marshaler, flattening nested Marshalers as
+// F.Set does
+func (e *fieldEncoder) add(key string, value any) {
+	logf.Marshal(key, value, func(key string, value any) {
+		e.fields = append(e.fields, encodedField{Field: Field{key: key}, value: value})
+	})
+}
+
+// addField adds a Field, expanding errors into their fields
+func (e *fieldEncoder) addField(f Field) {
+	switch f.kind {
+	case 0:
+	case fieldError:
+		e.fields = append(e.fields,
+			encodedField{Field: String("error.kind", "error")},
+			encodedField{Field: String("error.error", f.str)},
+		)
+	default:
+		e.fields = append(e.fields, encodedField{Field: f})
+	}
+}
+
+// encode writes the fields as a JSON object with sorted keys, the last
+// field of a key replacing the previous ones as in an F
+func (e *fieldEncoder) encode() (string, bool) {
+	slices.SortStableFunc(e.fields, func(a, b encodedField) int {
+		return strings.Compare(a.key, b.key)
+	})
+
+	r := currentRedactor()
+	b := append(e.buf, '{')
+	for i, f := range e.fields {
+		if i+1 < len(e.fields) && e.fields[i+1].key == f.key {
+			continue
+		}
+		if len(b) > 1 {
+			b = append(b, ',')
+		}
+		b = appendJSONString(b, f.key)
+		b = append(b, ':')
+
+		if r.redactsKey(f.key) {
+			b = appendJSONString(b, Redacted)
+			continue
+		}
+		if f.kind == 0 {
+			v, err := json.Marshal(r.Redact(f.key, f.value))
+			if err != nil {
+				return "", false
+			}
+			b = append(b, v...)
+			continue
+		}
+		switch f.kind {
+		case fieldString, fieldError:
+			b = appendJSONString(b, r.redactText(f.str))
+		case fieldInt, fieldDuration:
+			b = strconv.AppendInt(b, f.num, 10)
+		case fieldBool:
+			b = strconv.AppendBool(b, f.num != 0)
+		}
+	}
+	b = append(b, '}')
+	e.buf = b
+	return string(b), true
+}
+
+// hexDigits are the digits of the \u escapes
+const hexDigits = "0123456789abcdef"
+
+// appendJSONString appends s as a JSON string, escaped as encoding/json
+// does by default
+func appendJSONString(b []byte, s string) []byte {
+	b = append(b, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			if c >= 0x20 && c != '"' && c != '\\' && c != '<' && c != '>' && c != '&' {
+				i++
+				continue
+			}
+			b = append(b, s[start:i]...)
+			switch c {
+			case '"', '\\':
+				b = append(b, '\\', c)
+			case '\b':
+				b = append(b, '\\', 'b')
+			case '\f':
+				b = append(b, '\\', 'f')
+			case '\n':
+				b = append(b, '\\', 'n')
+			case '\r':
+				b = append(b, '\\', 'r')
+			case '\t':
+				b = append(b, '\\', 't')
+			default:
+				b = append(b, '\\', 'u', '0', '0', hexDigits[c>>4], hexDigits[c&0xf])
+			}
+			i++
+			start = i
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == utf8.RuneError && size == 1:
+			b = append(b, s[start:i]...)
+			b = utf8.AppendRune(b, utf8.RuneError)
+		case r == '\u2028' || r == '\u2029':
+			b = append(b, s[start:i]...)
+			b = append(b, '\\', 'u', '2', '0', '2', hexDigits[r&0xf])
+		default:
+			i += size
+			continue
+		}
+		i += size
+		start = i
+	}
+	b = append(b, s[start:]...)
+	return append(b, '"')
+}
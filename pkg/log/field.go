@@ -0,0 +1,103 @@
+// Copyright 2026 Outreach Corporation. All Rights Reserved.
+
+// Description: Provides typed log fields
+
+package log
+
+import (
+	"time"
+)
+
+// fieldKind is the type of the value of a Field
+type fieldKind uint8
+
+// the kinds of Field
+const (
+	fieldString fieldKind = iota + 1
+	fieldInt
+	fieldBool
+	fieldDuration
+	fieldError
+)
+
+// Field is a typed log field, built with String, Int, Int64, Bool,
+// Duration or Err. Unlike F, it does not allocate a map, and logs made
+// only of Fields are encoded without encoding/json:
+//
+//	log.Info(ctx, "request done", log.String("path", path), log.Duration("took", took))
+//
+// Fields can be mixed with any other Marshaler, and nested in an F.
+type Field struct {
+	key  string
+	kind fieldKind
+	str  string
+	num  int64
+}
+
+// String returns a Field with a string value.
+func String(key, value string) Field {
+	return Field{key: key, kind: fieldString, str: value}
+}
+
+// Int returns a Field with an int value.
+func Int(key string, value int) Field {
+	return Field{key: key, kind: fieldInt, num: int64(value)}
+}
+
+// Int64 returns a Field with an int64 value.
+func Int64(key string, value int64) Field {
+	return Field{key: key, kind: fieldInt, num: value}
+}
+
+// Bool returns a Field with a bool value.
+func Bool(key string, value bool) Field {
+	f := Field{key: key, kind: fieldBool}
+	if value {
+		f.num = 1
+	}
+	return f
+}
+
+// Duration returns a Field with a time.Duration value, logged in
+// nanoseconds as in an F.
+func Duration(key string, value time.Duration) Field {
+	return Field{key: key, kind: fieldDuration, num: int64(value)}
+}
+
+// Err returns a Field logging an error as the error.kind and error.error
+// fields, or nothing if err is nil. Use events.Err to also log the
+// message, stack and causes of the error.
+func Err(err error) Field {
+	if err == nil {
+		return Field{}
+	}
+	return Field{key: "error", kind: fieldError, str: err.Error()}
+}
+
+// value returns the value of the field as logged in an F
+func (f Field) value() any {
+	switch f.kind {
+	case fieldString, fieldError:
+		return f.str
+	case fieldInt:
+		return f.num
+	case fieldBool:
+		return f.num != 0
+	case fieldDuration:
+		return time.Duration(f.num)
+	default:
+		return nil
+	}
+}
+
+// MarshalLog implements the Marshaler interface.
+func (f Field) MarshalLog(addField func(key string, value any)) {
+	switch f.kind {
+	case 0:
+	case fieldError:
+		addField("error.kind", "error")
+		addField("error.error", f.str)
+	default:
+		addField(f.key, f.value())
+	}
+}
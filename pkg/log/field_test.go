@@ -0,0 +1,120 @@
+//go:build !or_e2e
+
+package log_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/getoutreach/gobox/pkg/log"
+	"gotest.tools/v3/assert"
+)
+
+type fieldSuite struct{}
+
+// timestampRegexp matches the timestamp of an entry
+var timestampRegexp = regexp.MustCompile(`"@timestamp":"[^"]*"`)
+
+// logLine returns the line logged by f, without its timestamp
+func logLine(t *testing.T, f func()) string {
+	var buf bytes.Buffer
+	out := log.Output()
+	log.SetOutput(&buf)
+	defer log.SetOutput(out)
+
+	f()
+	assert.Equal(t, strings.Count(buf.String(), "\n"), 1, buf.String())
+	return timestampRegexp.ReplaceAllString(buf.String(), `"@timestamp":""`)
+}
+
+func (fieldSuite) TestFieldsMatchF(t *testing.T) {
+	err := errors.New("failed: <nil> & \"quoted\"")
+	text := "tab\t nl\n ctl\x01 html<>& sep   bad\xff unicode é"
+
+	fields := logLine(t, func() {
+		log.Info(t.Context(), text,
+			log.String("s", text),
+			log.Int("i", -42),
+			log.Int64("i64", 1<<40),
+			log.Bool("yes", true),
+			log.Bool("no", false),
+			log.Duration("took", 1500*time.Millisecond),
+			log.Err(err),
+			log.Err(nil),
+			log.String("level", "overridden"),
+			log.String("token", "s3cr3t"),
+			log.String("contact", "mail jane@example.com"),
+		)
+	})
+	f := logLine(t, func() {
+		log.Info(t.Context(), text, log.F{
+			"s":       text,
+			"i":       -42,
+			"i64":     int64(1 << 40),
+			"yes":     true,
+			"no":      false,
+			"took":    1500 * time.Millisecond,
+			"level":   "overridden",
+			"token":   "s3cr3t",
+			"contact": "mail jane@example.com",
+		}, log.F{"error.kind": "error", "error.error": err.Error()})
+	})
+	assert.Equal(t, fields, f)
+}
+
+func (fieldSuite) TestFieldsNested(t *testing.T) {
+	nested := logLine(t, func() {
+		log.Warn(t.Context(), "nested", log.F{"db": log.Int("rows", 3)}, log.String("op", "select"))
+	})
+	assert.Assert(t, strings.Contains(nested, `"db.rows":3`), nested)
+	assert.Assert(t, strings.Contains(nested, `"op":"select"`), nested)
+}
+
+// benchmarkInfo logs with the given fields to io.Discard
+func benchmarkInfo(b *testing.B, m ...log.Marshaler) {
+	out := log.Output()
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(out)
+
+	ctx := context.Background()
+	b.ReportAllocs()
+	for b.Loop() {
+		log.Info(ctx, "request done", m...)
+	}
+}
+
+func BenchmarkInfoF(b *testing.B) {
+	benchmarkInfo(b, log.F{
+		"http.path":   "/api/v1/accounts",
+		"http.status": 200,
+		"took":        25 * time.Millisecond,
+		"cached":      true,
+	})
+}
+
+func BenchmarkInfoFields(b *testing.B) {
+	benchmarkInfo(b,
+		log.String("http.path", "/api/v1/accounts"),
+		log.Int("http.status", 200),
+		log.Duration("took", 25*time.Millisecond),
+		log.Bool("cached", true),
+	)
+}
+
+func BenchmarkInfoFieldsNoRedaction(b *testing.B) {
+	log.SetRedactor(nil)
+	defer log.SetRedactor(log.NewRedactor(log.DefaultRedactOptions()...))
+
+	benchmarkInfo(b,
+		log.String("http.path", "/api/v1/accounts"),
+		log.Int("http.status", 200),
+		log.Duration("took", 25*time.Millisecond),
+		log.Bool("cached", true),
+	)
+}
@@ -10,41 +10,78 @@ import (
 	"time"
 )
 
-// MaxItems is the maximum number of debug entries cached
+// MaxItems is the default maximum number of debug entries cached
 const MaxItems = 200
 
-// MaxDuration is the age past which a debug entry is considered stale.
+// MaxDuration is the default age past which a debug entry is considered stale.
 const MaxDuration = time.Minute * 2
 
-// New returns a new collection of log entries
+// New returns a new collection of log entries with the default limits
 func New() *Entries {
-	return &Entries{}
+	return NewWithLimits(MaxItems, MaxDuration)
+}
+
+// NewWithLimits returns a new collection of log entries holding at most
+// maxItems entries, no older than maxAge. Non-positive limits are
+// replaced by MaxItems and MaxDuration.
+func NewWithLimits(maxItems int, maxAge time.Duration) *Entries {
+	e := &Entries{}
+	e.maxItems, e.maxAge = limits(maxItems, maxAge)
+	return e
+}
+
+// limits replaces non-positive limits by the defaults
+func limits(maxItems int, maxAge time.Duration) (int, time.Duration) {
+	if maxItems <= 0 {
+		maxItems = MaxItems
+	}
+	if maxAge <= 0 {
+		maxAge = MaxDuration
+	}
+	return maxItems, maxAge
 }
 
 // Entries holds a limited size buffer of formatted debug entries
 type Entries struct { //nolint:gocritic // Why: Will refactor in the future
 	sync.Mutex
-	items []item
+	items    []item
+	maxItems int
+	maxAge   time.Duration
+}
+
+// SetLimits changes the maximum number of entries held and their maximum
+// age. Entries past the new limits are dropped on the next append.
+// Non-positive limits are replaced by the defaults, as in NewWithLimits.
+func (e *Entries) SetLimits(maxItems int, maxAge time.Duration) {
+	e.Lock()
+	defer e.Unlock()
+
+	e.maxItems, e.maxAge = limits(maxItems, maxAge)
 }
 
 func (e *Entries) Append(message string) {
 	e.Lock()
 	defer e.Unlock()
 
-	e.items = append(e.items, item{message, time.Now()})
-	if len(e.items) > MaxItems {
-		e.items = e.items[1:]
+	now := time.Now()
+	e.items = append(e.items, item{message, now})
+
+	drop := max(len(e.items)-e.maxItems, 0)
+	for drop < len(e.items) && now.Sub(e.items[drop].ts) > e.maxAge {
+		drop++
 	}
+	e.items = e.items[drop:]
 }
 
 func (e *Entries) Flush(write func(s string)) {
 	e.Lock()
 	items := e.items
+	maxAge := e.maxAge
 	e.items = nil
 	e.Unlock()
 
 	for _, entry := range items {
-		if time.Since(entry.ts) <= MaxDuration {
+		if time.Since(entry.ts) <= maxAge {
 			write(entry.s)
 		}
 	}
@@ -3,10 +3,13 @@
 package entries_test
 
 import (
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/getoutreach/gobox/pkg/log/internal/entries"
 	"github.com/getoutreach/gobox/pkg/shuffler"
+	"gotest.tools/v3/assert"
 )
 
 func TestAll(t *testing.T) {
@@ -45,3 +48,44 @@ func (suite) TestAppendNoBlock(t *testing.T) {
 	}
 	close(unblock)
 }
+
+func (suite) TestLimits(t *testing.T) {
+	items := entries.NewWithLimits(3, time.Minute)
+	for i := range 5 {
+		items.Append(strconv.Itoa(i))
+	}
+
+	var flushed []string
+	items.Flush(func(s string) { flushed = append(flushed, s) })
+	assert.DeepEqual(t, flushed, []string{"2", "3", "4"})
+
+	// stale entries are not flushed
+	items.SetLimits(3, time.Nanosecond)
+	items.Append("stale")
+	time.Sleep(time.Millisecond)
+	flushed = nil
+	items.Flush(func(s string) { flushed = append(flushed, s) })
+	assert.Equal(t, len(flushed), 0)
+}
+
+func (suite) TestNonPositiveLimits(t *testing.T) {
+	for _, items := range []*entries.Entries{entries.NewWithLimits(-1, 0), entries.New()} {
+		items.SetLimits(-1, -time.Second)
+		for i := range entries.MaxItems + 1 {
+			items.Append(strconv.Itoa(i))
+		}
+
+		// the default limits apply: the entries are kept, but the oldest
+		var flushed []string
+		items.Flush(func(s string) { flushed = append(flushed, s) })
+		assert.Equal(t, len(flushed), entries.MaxItems)
+		assert.Equal(t, flushed[0], "1")
+	}
+
+	items := entries.NewWithLimits(0, 0)
+	items.Append("one")
+	items.Append("two")
+	var flushed []string
+	items.Flush(func(s string) { flushed = append(flushed, s) })
+	assert.DeepEqual(t, flushed, []string{"one", "two"})
+}
@@ -11,9 +11,34 @@
 //	log.Debug(...)
 //	log.Fatal(...)
 //
+// On hot paths, typed fields such as log.String, log.Int, log.Duration and
+// log.Err avoid building a log.F map, and logs made only of them are
+// encoded directly:
+//
+//	log.Info(ctx, "message", log.Int("field", 42))
+//
 // By default, log.Debug is not emitted but instead it is cached. If
-// a higher event arrives within a couple of minutes of the debug log,
-// the cached debug log is emitted (with the correct older timestamp).
+// an error or fatal event arrives within a couple of minutes of the debug
+// log, the cached debug log is emitted (with the correct older timestamp).
+// The number and age of cached logs are set with SetDebugBufferLimits.
+// Calls started with trace.StartCall have their own cache (see
+// WithDebugBuffer), so an error only emits the debug logs of its call.
+//
+// Logs are written as JSON lines to the Output writer, stdout by default.
+// SetSink sends them to a Sink instead: the package provides sinks
+// writing to rotated files, to the local syslog or journald, in the
+// background, or as human readable text, which FilterLevel and FanOut
+// combine. SetOutput and logtest only capture logs when no sink is set.
+//
+// SetSampling limits the logs of hot call sites, such as an error logged
+// in a tight loop, by sampling them and suppressing repeated messages.
+//
+// Fields which look sensitive, such as tokens, passwords and JSON Web
+// Tokens, are redacted from every log by default, so such fields which
+// used to be logged as is now read "redacted". Email addresses are not,
+// unless RedactEmails is added to the rules with SetRedactor, which changes
+// the rules; SetRedactor(nil) turns redaction off. logtest.AssertNoSecrets
+// checks that tests leak nothing.
 //
 // # Guidance on what type of log to use
 //
@@ -30,7 +55,6 @@ import (
 	"log/slog"
 	"math"
 	"os"
-	"runtime"
 	"runtime/debug"
 	"sort"
 	"strings"
@@ -145,6 +169,10 @@ func Write(s string) {
 	}
 }
 
+// LevelFatal is the level of Fatal logs, the OpenTelemetry FATAL level (21) as
+// recommended by the slog documentation. See https://pkg.go.dev/log/slog#Level
+const LevelFatal = slog.Level(21)
+
 // F is a map of fields used for logging:
 //
 //	log.Info(ctx, "request started", log.F{"start_time": time.Now()})
@@ -158,12 +186,14 @@ type F = logf.F
 // It captures the caller's program counter (skipping internal log frames)
 // and passes it to slog for accurate source location reporting.
 func slogIt(ctx context.Context, lvl slog.Level, message string, m []Marshaler) {
-	once.Do(setupSlog)
-	var pcs [1]uintptr
+	slogAt(ctx, lvl, message, m, callerPC())
+}
 
-	runtime.Callers(3, pcs[:]) // skip [Callers, slogIt, gobox log function]
+// slogAt is slogIt for a line whose source is the program counter pc
+func slogAt(ctx context.Context, lvl slog.Level, message string, m []Marshaler, pc uintptr) {
+	once.Do(setupSlog)
 
-	r := slog.NewRecord(time.Now(), lvl, message, pcs[0])
+	r := slog.NewRecord(time.Now(), lvl, currentRedactor().redactText(message), pc)
 	r.AddAttrs(slogAttrs(m)...)
 
 	// Acquire lock to safely read the log variable
@@ -183,62 +213,88 @@ func Debug(ctx context.Context, message string, m ...Marshaler) {
 		slogIt(ctx, slog.LevelDebug, message, m)
 		return
 	}
-	dbgEntries.Append(format(ctx, message, "DEBUG", time.Now(), app.Info(), m))
+	debugEntries(ctx).Append(format(ctx, message, "DEBUG", time.Now(), app.Info(), m))
 }
 
 // Info emits a log at INFO level. This is not filtered and meant for non-debug information.
 func Info(ctx context.Context, message string, m ...Marshaler) {
+	m, ok := sample(ctx, slog.LevelInfo, message, m)
+	if !ok {
+		return
+	}
 	if ShouldUseSlog() {
 		slogIt(ctx, slog.LevelInfo, message, m)
 		return
 	}
 	s := format(ctx, message, "INFO", time.Now(), app.Info(), m)
 
-	Write(s)
+	writeEntry(slog.LevelInfo, s)
 }
 
 // Warn emits a log at WARN level. Warn logs are meant to be investigated if they reach high volumes.
 func Warn(ctx context.Context, message string, m ...Marshaler) {
+	m, ok := sample(ctx, slog.LevelWarn, message, m)
+	if !ok {
+		return
+	}
 	if ShouldUseSlog() {
 		slogIt(ctx, slog.LevelWarn, message, m)
 		return
 	}
 	s := format(ctx, message, "WARN", time.Now(), app.Info(), m)
 
-	Write(s)
+	writeEntry(slog.LevelWarn, s)
 }
 
 // Error emits a log at ERROR level.  Error logs must be investigated
 func Error(ctx context.Context, message string, m ...Marshaler) {
+	m, ok := sample(ctx, slog.LevelError, message, m)
+	if !ok {
+		return
+	}
 	if ShouldUseSlog() {
 		slogIt(ctx, slog.LevelError, message, m)
 		return
 	}
-	dbgEntries.Flush(Write)
+	debugEntries(ctx).Flush(writeDebug)
 	s := format(ctx, message, "ERROR", time.Now(), app.Info(), m)
 
-	Write(s)
+	writeEntry(slog.LevelError, s)
 }
 
 // Fatal emits a log at FATAL level and exits.  This is for catastrophic unrecoverable errors.
 func Fatal(ctx context.Context, message string, m ...Marshaler) {
 	if ShouldUseSlog() {
-		// Use OpenTelemetry FATAL level (21) as recommended by slog documentation
-		// See https://pkg.go.dev/log/slog#Level
-		const LevelFatal = slog.Level(21)
 		slogIt(ctx, LevelFatal, message, m)
 		os.Exit(1)
 		return
 	}
-	dbgEntries.Flush(Write)
+	debugEntries(ctx).Flush(writeDebug)
 	s := format(ctx, message, "FATAL", time.Now(), app.Info(), m)
 
-	Write(s)
+	writeEntry(LevelFatal, s)
+	syncSink()
 
 	os.Exit(1)
 }
 
+// logAt emits a log at the given level, other than DEBUG and FATAL, whose
+// source is the program counter pc
+func logAt(ctx context.Context, lvl slog.Level, message string, m []Marshaler, pc uintptr) {
+	if ShouldUseSlog() {
+		slogAt(ctx, lvl, message, m, pc)
+		return
+	}
+	writeEntry(lvl, format(ctx, message, lvl.String(), time.Now(), app.Info(), m))
+}
+
 func format(ctx context.Context, msg, level string, ts time.Time, appInfo Marshaler, mm Many) string {
+	if level != "FATAL" && onlyFields(mm) {
+		if s, ok := encodeFields(ctx, msg, level, ts, appInfo, mm); ok {
+			return s
+		}
+	}
+
 	entry := F{"message": msg, "level": level, "@timestamp": ts.Format(time.RFC3339Nano)}
 
 	appInfo.MarshalLog(entry.Set)
@@ -249,12 +305,14 @@ func format(ctx context.Context, msg, level string, ts time.Time, appInfo Marsha
 		entry.Set("traceID", span.SpanContext().TraceID().String())
 	}
 
-	addSource(entry)
+	addSource(func(key string, value any) { entry[key] = value })
 
 	if entry["level"] == "FATAL" {
 		generateFatalFields(entry)
 	}
 
+	redactEntry(entry)
+
 	if len(entry) == 0 {
 		return ""
 	}
@@ -280,7 +338,7 @@ func format(ctx context.Context, msg, level string, ts time.Time, appInfo Marsha
 	return strings.TrimSpace(b.String())
 }
 
-func addSource(entry F) {
+func addSource(set func(key string, value any)) {
 	// Attempt to map the caller of the log function into the "module" field for identifying if a service or a module
 	// that the service is using is sending logs (costing money).
 	// Skip 3 levels to start, and we may go further below (to skip log.With, other wrappers, etc.):
@@ -291,7 +349,7 @@ func addSource(entry F) {
 	for {
 		ci, err := callerinfo.GetCallerInfo(skips)
 		if err != nil {
-			entry["module"] = "error"
+			set("module", "error")
 			break
 		}
 
@@ -303,23 +361,28 @@ func addSource(entry F) {
 		}
 
 		if ci.Module != "" {
-			entry["module"] = ci.Module
+			set("module", ci.Module)
 			if ci.ModuleVersion != "" {
-				entry["modulever"] = ci.ModuleVersion
+				set("modulever", ci.ModuleVersion)
 			}
 		}
 		break
 	}
 }
 
-// Flush writes out all debug logs
-func Flush(_ context.Context) {
-	dbgEntries.Flush(Write)
+// Flush writes out all debug logs, those of the context's debug buffer if it has one
+// and the global ones
+func Flush(ctx context.Context) {
+	if buf := debugEntries(ctx); buf != dbgEntries {
+		buf.Flush(writeDebug)
+	}
+	dbgEntries.Flush(writeDebug)
 }
 
 // Purge clears all debug logs without writing them out. This is useful to clear logs
 // from a successful tests that we don't want output during a subsequent test
-func Purge(_ context.Context) {
+func Purge(ctx context.Context) {
+	debugEntries(ctx).Purge()
 	dbgEntries.Purge()
 }
 
@@ -346,8 +409,9 @@ func slogAttrs(arg logf.Many) []slog.Attr {
 
 	var kvs []keyValue
 
+	redactor := currentRedactor()
 	logf.Marshal("", arg, func(key string, value any) {
-		kvs = append(kvs, keyValue{key: key, value: value})
+		kvs = append(kvs, keyValue{key: key, value: redactor.Redact(key, value)})
 	})
 
 	// Sort by key to ensure consistent ordering
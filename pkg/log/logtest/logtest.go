@@ -14,7 +14,10 @@ package logtest
 
 import (
 	"encoding/json"
+	"fmt"
 	"io"
+	"reflect"
+	"strings"
 	"sync"
 	"testing"
 
@@ -62,6 +65,40 @@ func (l *LogRecorder) Entries() []log.F {
 	return l.entries[:len(l.entries):len(l.entries)]
 }
 
+// AssertNoSecrets fails the test if a recorded entry leaks a secret, see
+// AssertNoSecrets.
+func (l *LogRecorder) AssertNoSecrets(secrets ...string) {
+	l.Helper()
+	AssertNoSecrets(l.T, l.Entries(), secrets...)
+}
+
+// AssertNoSecrets fails the test if an entry contains one of the secrets,
+// or has a field that the default redaction rules redact, such as a token
+// field or a JSON Web Token (but not an email address, see log.RedactEmails):
+//
+//	logs := logtest.NewLogRecorder(t)
+//	defer logs.Close()
+//	client.Login(ctx, user, password)
+//	logs.AssertNoSecrets(password)
+func AssertNoSecrets(t testing.TB, entries []log.F, secrets ...string) {
+	t.Helper()
+
+	r := log.NewRedactor(log.DefaultRedactOptions()...)
+	for _, entry := range entries {
+		for k, v := range entry {
+			s := fmt.Sprint(v)
+			for _, secret := range secrets {
+				if secret != "" && strings.Contains(s, secret) {
+					t.Errorf("log %q leaks a secret in field %s", entry["message"], k)
+				}
+			}
+			if !reflect.DeepEqual(r.Redact(k, v), v) {
+				t.Errorf("log %q has an unredacted field %s", entry["message"], k)
+			}
+		}
+	}
+}
+
 // MarshalToMap uses the given arguments `MarshalLog` function to serialize it
 // into a map, which it returns.
 //
@@ -0,0 +1,267 @@
+// Copyright 2026 Outreach Corporation. All Rights Reserved.
+
+// Description: Provides redaction of sensitive fields from logs
+
+package log
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Redacted replaces the sensitive values in logs.
+const Redacted = "redacted"
+
+// Redactor removes sensitive values from log fields, by rules on the key
+// of the field, the content of string values and the Go type of values.
+// Its zero value redacts nothing.
+type Redactor struct {
+	keys   []*regexp.Regexp
+	values []valueRule
+	types  map[reflect.Type]struct{}
+
+	// keyCache caches the results of the key rules, keys are a small set
+	// in practice but cachedKeys bounds it anyway
+	keyCache   sync.Map
+	cachedKeys atomic.Int64
+}
+
+// maxCachedKeys is the number of keys past which the results of the key
+// rules are no longer cached
+const maxCachedKeys = 10000
+
+// valueRule is a value rule of a Redactor, whose regular expression is only
+// run on the values containing one of the literals, if any
+type valueRule struct {
+	re       *regexp.Regexp
+	literals []string
+}
+
+// mayMatch reports whether the rule can match s
+func (v valueRule) mayMatch(s string) bool {
+	if len(v.literals) == 0 {
+		return true
+	}
+	for _, lit := range v.literals {
+		if strings.Contains(s, lit) {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactOption adds rules to a Redactor.
+type RedactOption func(*Redactor)
+
+// RedactKeys redacts the fields whose key matches one of the patterns.
+// Patterns are matched against the last segment of the key, so that
+// "authorization" matches the http.request.headers.authorization field,
+// and the whole segment must match, case insensitively.
+func RedactKeys(patterns ...string) RedactOption {
+	return func(r *Redactor) {
+		if len(patterns) == 0 {
+			return
+		}
+		r.keys = append(r.keys, regexp.MustCompile(`(?i)^(?:(?:`+strings.Join(patterns, `)|(?:`)+`))$`))
+	}
+}
+
+// RedactValues redacts the parts of string and error values, including
+// the message, which match one of the regular expressions.
+func RedactValues(res ...*regexp.Regexp) RedactOption {
+	return func(r *Redactor) {
+		for _, re := range res {
+			r.values = append(r.values, valueRule{re: re})
+		}
+	}
+}
+
+// RedactType redacts the values of type T, and of type *T.
+func RedactType[T any]() RedactOption {
+	return func(r *Redactor) {
+		if r.types == nil {
+			r.types = make(map[reflect.Type]struct{})
+		}
+		r.types[reflect.TypeFor[T]()] = struct{}{}
+	}
+}
+
+// nolint:gochecknoglobals // Why: default rules of the redaction, see DefaultRedactOptions
+var (
+	// defaultRedactKeys are the keys of credentials
+	defaultRedactKeys = []string{
+		`pass(word|wd)?`, `.*secret.*`, `(.*[_-])?token`, `api[_-]?key`,
+		`authorization`, `(set-)?cookie`, `credentials?`, `private[_-]?key`,
+	}
+
+	// jwtRegexp matches JSON Web Tokens
+	jwtRegexp = regexp.MustCompile(`\beyJ[\w-]+\.[\w-]+\.[\w-]+`)
+
+	// awsKeyRegexp matches AWS access key IDs
+	awsKeyRegexp = regexp.MustCompile(`\b(AKIA|ASIA|ABIA|ACCA)[0-9A-Z]{16}\b`)
+
+	// emailRegexp matches email addresses
+	emailRegexp = regexp.MustCompile(`[\w.%+-]+@[\w-]+(\.[\w-]+)*\.[A-Za-z]{2,}`)
+)
+
+// DefaultRedactOptions returns the rules of the default Redactor: the keys
+// of passwords, secrets, tokens, API keys, authorization headers, cookies,
+// credentials and private keys, and values containing JSON Web Tokens and
+// AWS access key IDs. Email addresses are not redacted by default, see
+// RedactEmails.
+func DefaultRedactOptions() []RedactOption {
+	return []RedactOption{
+		RedactKeys(defaultRedactKeys...),
+		func(r *Redactor) {
+			r.values = append(r.values,
+				valueRule{re: jwtRegexp, literals: []string{"eyJ"}},
+				valueRule{re: awsKeyRegexp, literals: []string{"AKIA", "ASIA", "ABIA", "ACCA"}},
+			)
+		},
+	}
+}
+
+// RedactEmails redacts the email addresses in string and error values,
+// including the message. It is not part of DefaultRedactOptions since
+// many logs rely on email addresses, such as those of support tools, and
+// must be added to them:
+//
+//	log.SetRedactor(log.NewRedactor(append(log.DefaultRedactOptions(), log.RedactEmails())...))
+func RedactEmails() RedactOption {
+	return func(r *Redactor) {
+		r.values = append(r.values, valueRule{re: emailRegexp, literals: []string{"@"}})
+	}
+}
+
+// NewRedactor returns a Redactor with the given rules. Extend the default
+// rules by passing DefaultRedactOptions:
+//
+//	log.SetRedactor(log.NewRedactor(append(log.DefaultRedactOptions(),
+//		log.RedactKeys("ssn"),
+//		log.RedactType[cfg.SecretData](),
+//	)...))
+func NewRedactor(opts ...RedactOption) *Redactor {
+	r := &Redactor{}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Redact returns the value of a field as it is logged: Redacted if the key
+// or the type of the value match a rule, or the value with the matching
+// parts of its text replaced by Redacted.
+func (r *Redactor) Redact(key string, value any) any {
+	if r == nil || value == nil {
+		return value
+	}
+
+	if r.redactsKey(key) {
+		return Redacted
+	}
+
+	if len(r.types) > 0 {
+		t := reflect.TypeOf(value)
+		if t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		if _, ok := r.types[t]; ok {
+			return Redacted
+		}
+	}
+
+	// unchanged values are returned as is, re-boxing a string would
+	// allocate on every field
+	switch v := value.(type) {
+	case string:
+		if redacted := r.redactText(v); redacted != v {
+			return redacted
+		}
+	case error:
+		s := v.Error()
+		if redacted := r.redactText(s); redacted != s {
+			return redacted
+		}
+	}
+	return value
+}
+
+// redactsKey reports whether the fields of the key are redacted
+func (r *Redactor) redactsKey(key string) bool {
+	if r == nil || len(r.keys) == 0 {
+		return false
+	}
+	if redacts, ok := r.keyCache.Load(key); ok {
+		return redacts.(bool)
+	}
+
+	redacts := false
+	segment := key[strings.LastIndex(key, ".")+1:]
+	for _, re := range r.keys {
+		if re.MatchString(segment) {
+			redacts = true
+			break
+		}
+	}
+	if r.cachedKeys.Add(1) <= maxCachedKeys {
+		r.keyCache.Store(key, redacts)
+	}
+	return redacts
+}
+
+// redactText replaces the parts of s matching the value rules
+func (r *Redactor) redactText(s string) string {
+	if r == nil {
+		return s
+	}
+	for _, v := range r.values {
+		if v.mayMatch(s) {
+			s = v.re.ReplaceAllString(s, Redacted)
+		}
+	}
+	return s
+}
+
+// nolint:gochecknoglobals // Why: sets up the overwritable redactor
+var (
+	redactorLock = new(sync.RWMutex)
+	redactor     = NewRedactor(DefaultRedactOptions()...)
+)
+
+// SetRedactor sets the Redactor applied to the fields and message of every
+// log, both by this package's formatter and the slog facade. nil turns
+// redaction off.
+//
+// Redaction is on by default, with DefaultRedactOptions: fields which used
+// to be logged as is, such as a "token" field, are now logged as Redacted.
+// The value rules only run their regular expressions on the strings
+// containing their literal prefixes, so most fields only cost a substring
+// search.
+// Note: this function should not be used in production code outside of service startup.
+func SetRedactor(r *Redactor) {
+	redactorLock.Lock()
+	defer redactorLock.Unlock()
+
+	redactor = r
+}
+
+// currentRedactor returns the Redactor set by SetRedactor
+func currentRedactor() *Redactor {
+	redactorLock.RLock()
+	defer redactorLock.RUnlock()
+	return redactor
+}
+
+// redactEntry applies the redactor to the fields of an entry
+func redactEntry(entry F) {
+	r := currentRedactor()
+	if r == nil {
+		return
+	}
+	for k, v := range entry {
+		entry[k] = r.Redact(k, v)
+	}
+}
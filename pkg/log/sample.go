@@ -0,0 +1,357 @@
+// Copyright 2026 Outreach Corporation. All Rights Reserved.
+
+// Description: Provides sampling and deduplication of hot log lines
+
+package log
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// maxSampledSites is the number of call sites and messages past which the
+// expired deduplication windows are discarded, and new ones are not started
+const maxSampledSites = 4096
+
+// SamplingOptions are the options of SetSampling.
+type SamplingOptions struct {
+	// Interval is the period over which the lines of a call site are
+	// counted, a second if 0.
+	Interval time.Duration
+
+	// First is the number of lines of a call site emitted per interval, 0
+	// to turn sampling off.
+	First int
+
+	// Thereafter is the rate at which the lines past First are emitted,
+	// one in Thereafter, 0 to drop them all.
+	Thereafter int
+
+	// DedupWindow is the time during which a line with the same message as
+	// a previous line of its call site is suppressed, 0 to turn
+	// deduplication off.
+	DedupWindow time.Duration
+}
+
+// nolint:gochecknoglobals // Why: sets up overwritable sampling
+var (
+	samplingLock = new(sync.Mutex)
+	sampling     SamplingOptions
+
+	// samplingOn is set when sampling or deduplication is on, so that
+	// lines are not serialized on samplingLock by default
+	samplingOn atomic.Bool
+
+	sampleSites map[callSite]*sampleSite
+	dedupLines  map[dedupKey]*dedupLine
+	callSites   = map[uintptr]callSite{}
+
+	// dedupTimer emits the summaries of the deduplication windows which
+	// are over, it is armed while lines are suppressed. dedupGen is
+	// incremented by SetSampling to disarm it.
+	dedupTimer *time.Timer
+	dedupGen   int
+)
+
+// callSite is the source location of a log call. Several program counters
+// can map to the same call site when the caller is inlined.
+type callSite struct {
+	file string
+	line int
+}
+
+// sampleSite counts the lines of a call site
+type sampleSite struct {
+	start   time.Time
+	count   int
+	dropped int
+}
+
+// dedupKey identifies a line for deduplication
+type dedupKey struct {
+	site    callSite
+	message string
+}
+
+// dedupLine counts the suppressed repeats of a line. level and pc are
+// those of the first line of the window.
+type dedupLine struct {
+	start      time.Time
+	suppressed int
+	level      slog.Level
+	pc         uintptr
+}
+
+// summary returns the summary of the window of the line
+func (l *dedupLine) summary(message string) dedupSummary {
+	return dedupSummary{message: message, level: l.level, pc: l.pc, suppressed: l.suppressed}
+}
+
+// dedupSummary is the summary line of a deduplication window
+type dedupSummary struct {
+	message    string
+	level      slog.Level
+	pc         uintptr
+	suppressed int
+}
+
+// emit logs the summary at the call site of its window
+func (s dedupSummary) emit(ctx context.Context) {
+	logAt(ctx, s.level, s.message, []Marshaler{F{"log.suppressed": s.suppressed}}, s.pc)
+}
+
+// SetSampling sets how the Info, Warn and Error logs of each call site are
+// sampled and deduplicated, to keep a hot loop from emitting thousands of
+// lines. The zero SamplingOptions, the default, turns both off.
+//
+// With sampling, the First lines of a call site are emitted each Interval,
+// then one in Thereafter. The next line emitted by the call site has a
+// "log.dropped" field counting the lines dropped before it.
+//
+// With deduplication, a line is suppressed if its call site logged the same
+// message less than DedupWindow ago. Once the window is over, a summary
+// line, with the same message and level, whose "log.suppressed" field
+// counts the suppressed lines, is emitted before the next line of the call
+// site, or when the window ends if the call site does not log again.
+// Lines are not deduplicated while 4096 windows are in progress, and
+// SetSampling emits the summaries of the windows in progress.
+//
+// Deduplication applies before sampling, and summary lines are not
+// sampled. Debug and Fatal logs are neither sampled nor deduplicated.
+// Note: this function should not be used in production code outside of service startup.
+func SetSampling(opts SamplingOptions) {
+	if opts.Interval <= 0 {
+		opts.Interval = time.Second
+	}
+
+	samplingLock.Lock()
+	summaries := expireDedup(sampling.DedupWindow, time.Now(), true)
+	sampling = opts
+	samplingOn.Store(opts.First != 0 || opts.DedupWindow != 0)
+	sampleSites = nil
+	dedupLines = nil
+	if dedupTimer != nil {
+		dedupTimer.Stop()
+		dedupTimer = nil
+	}
+	dedupGen++
+	samplingLock.Unlock()
+
+	for _, summary := range summaries {
+		summary.emit(context.Background())
+	}
+}
+
+// sample decides whether a line is emitted, returning its fields with the
+// sampling fields added. It emits the deduplication summaries which are
+// due.
+func sample(ctx context.Context, lvl slog.Level, message string, m []Marshaler) ([]Marshaler, bool) {
+	if !samplingOn.Load() {
+		return m, true
+	}
+
+	samplingLock.Lock()
+	opts := sampling
+	if opts.First == 0 && opts.DedupWindow == 0 {
+		samplingLock.Unlock()
+		return m, true
+	}
+
+	pc := callerPC()
+	site := callSiteOf(pc)
+	now := time.Now()
+
+	summary, expired, emit := dedup(opts, dedupKey{site: site, message: message}, lvl, pc, now)
+	dropped := 0
+	if emit {
+		dropped, emit = sampleSiteLine(opts, site, now)
+	}
+	samplingLock.Unlock()
+
+	// the summaries of other call sites are not part of this context
+	for _, s := range expired {
+		s.emit(context.Background())
+	}
+	if summary.suppressed > 0 {
+		summary.emit(ctx)
+	}
+	if dropped > 0 {
+		m = append(m[:len(m):len(m)], F{"log.dropped": dropped})
+	}
+	return m, emit
+}
+
+// dedup counts a line against its deduplication window, returning the
+// summary of its previous window, the summaries of the windows expired to
+// make room for it and whether the line is emitted. samplingLock must be
+// held.
+func dedup(opts SamplingOptions, key dedupKey, lvl slog.Level, pc uintptr, now time.Time) (
+	summary dedupSummary, expired []dedupSummary, emit bool) {
+	if opts.DedupWindow == 0 {
+		return summary, nil, true
+	}
+	if dedupLines == nil {
+		dedupLines = make(map[dedupKey]*dedupLine)
+	}
+
+	line, ok := dedupLines[key]
+	if !ok {
+		if len(dedupLines) >= maxSampledSites {
+			expired = expireDedup(opts.DedupWindow, now, false)
+			if len(dedupLines) >= maxSampledSites {
+				// every window is in progress, the line is emitted
+				// without being deduplicated
+				return summary, expired, true
+			}
+		}
+		dedupLines[key] = &dedupLine{start: now, level: lvl, pc: pc}
+		return summary, expired, true
+	}
+	if now.Sub(line.start) < opts.DedupWindow {
+		line.suppressed++
+		if dedupTimer == nil {
+			armDedupTimer(line.start.Add(opts.DedupWindow).Sub(now))
+		}
+		return summary, nil, false
+	}
+
+	summary = line.summary(key.message)
+	*line = dedupLine{start: now, level: lvl, pc: pc}
+	return summary, nil, true
+}
+
+// expireDedup discards the deduplication windows which are over, or all
+// of them, returning the summaries of those which suppressed lines.
+// samplingLock must be held.
+func expireDedup(window time.Duration, now time.Time, all bool) []dedupSummary {
+	var summaries []dedupSummary
+	for key, line := range dedupLines {
+		if !all && now.Sub(line.start) < window {
+			continue
+		}
+		if line.suppressed > 0 {
+			summaries = append(summaries, line.summary(key.message))
+		}
+		delete(dedupLines, key)
+	}
+	return summaries
+}
+
+// armDedupTimer arms the timer emitting the summaries of the windows which
+// are over in d. samplingLock must be held.
+func armDedupTimer(d time.Duration) {
+	gen := dedupGen
+	dedupTimer = time.AfterFunc(d, func() { flushDedup(gen) })
+}
+
+// flushDedup emits the summaries of the deduplication windows which are
+// over, so that they are not lost if their call site does not log again,
+// and arms the timer again for the windows which still suppress lines.
+func flushDedup(gen int) {
+	samplingLock.Lock()
+	if gen != dedupGen {
+		// SetSampling emitted the summaries
+		samplingLock.Unlock()
+		return
+	}
+
+	window := sampling.DedupWindow
+	now := time.Now()
+	dedupTimer = nil
+	summaries := expireDedup(window, now, false)
+
+	var next time.Duration
+	for _, line := range dedupLines {
+		if d := line.start.Add(window).Sub(now); line.suppressed > 0 && (next == 0 || d < next) {
+			next = d
+		}
+	}
+	if next > 0 {
+		armDedupTimer(next)
+	}
+	samplingLock.Unlock()
+
+	for _, summary := range summaries {
+		summary.emit(context.Background())
+	}
+}
+
+// sampleSiteLine counts a line against the sampling of its call site,
+// returning the number of lines dropped since the last emitted one and
+// whether the line is emitted. samplingLock must be held.
+func sampleSiteLine(opts SamplingOptions, key callSite, now time.Time) (dropped int, emit bool) {
+	if opts.First == 0 {
+		return 0, true
+	}
+	if sampleSites == nil {
+		sampleSites = make(map[callSite]*sampleSite)
+	}
+
+	site, ok := sampleSites[key]
+	if !ok {
+		site = &sampleSite{start: now}
+		sampleSites[key] = site
+	}
+	if now.Sub(site.start) >= opts.Interval {
+		site.start = now
+		site.count = 0
+	}
+
+	site.count++
+	past := site.count - opts.First
+	if past > 0 && (opts.Thereafter == 0 || past%opts.Thereafter != 0) {
+		site.dropped++
+		return 0, false
+	}
+
+	dropped = site.dropped
+	site.dropped = 0
+	return dropped, true
+}
+
+// callSiteOf returns the call site of a program counter. samplingLock must
+// be held.
+func callSiteOf(pc uintptr) callSite {
+	if site, ok := callSites[pc]; ok {
+		return site
+	}
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	site := callSite{file: frame.File, line: frame.Line}
+	callSites[pc] = site
+	return site
+}
+
+// callerPC returns the program counter of the caller of the log function,
+// skipping the packages in packageSourceInfoSkips as addSource does.
+func callerPC() uintptr {
+	var pcs [32]uintptr
+	n := runtime.Callers(2, pcs[:]) // skip [Callers, callerPC]
+	for _, pc := range pcs[:n] {
+		fn := runtime.FuncForPC(pc - 1)
+		if fn == nil {
+			return pc
+		}
+		if _, has := packageSourceInfoSkips[funcPackage(fn.Name())]; !has {
+			return pc
+		}
+	}
+	if n == 0 {
+		return 0
+	}
+	return pcs[n-1]
+}
+
+// funcPackage returns the package of a function name such as
+// github.com/getoutreach/gobox/pkg/log.logger.Info
+func funcPackage(name string) string {
+	slash := strings.LastIndex(name, "/") + 1
+	if dot := strings.Index(name[slash:], "."); dot != -1 {
+		return name[:slash+dot]
+	}
+	return name
+}
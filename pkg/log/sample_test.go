@@ -0,0 +1,144 @@
+//go:build !or_e2e
+
+package log_test
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/getoutreach/gobox/pkg/log"
+	"github.com/getoutreach/gobox/pkg/log/logtest"
+	"gotest.tools/v3/assert"
+)
+
+type sampleSuite struct{}
+
+func (sampleSuite) TestSampling(t *testing.T) {
+	logs := logtest.NewLogRecorder(t)
+	defer logs.Close()
+	defer log.SetSampling(log.SamplingOptions{})
+
+	log.SetSampling(log.SamplingOptions{Interval: time.Hour, First: 2, Thereafter: 3})
+	for i := range 10 {
+		log.Warn(t.Context(), "hot", log.F{"i": i})
+	}
+	log.Warn(t.Context(), "other call site")
+
+	var got []any
+	for _, entry := range logs.Entries() {
+		got = append(got, entry["i"])
+	}
+	// lines 0 and 1, then one in 3 of the others
+	assert.DeepEqual(t, got, []any{0.0, 1.0, 4.0, 7.0, nil})
+	assert.Equal(t, logs.Entries()[2]["log.dropped"], 2.0)
+	assert.Equal(t, logs.Entries()[3]["log.dropped"], 2.0)
+}
+
+func (sampleSuite) TestDedup(t *testing.T) {
+	logs := logtest.NewLogRecorder(t)
+	defer logs.Close()
+	defer log.SetSampling(log.SamplingOptions{})
+
+	log.SetSampling(log.SamplingOptions{DedupWindow: 50 * time.Millisecond})
+	logLoop := func(n int) {
+		for range n {
+			log.Error(t.Context(), "connection refused")
+		}
+	}
+	logLoop(5)
+	log.Error(t.Context(), "different call site")
+	time.Sleep(60 * time.Millisecond)
+	logLoop(3)
+
+	entries := logs.Entries()
+	assert.DeepEqual(t, messages(entries), []string{
+		"connection refused", "different call site", "connection refused", "connection refused",
+	})
+	assert.Equal(t, entries[2]["log.suppressed"], 4.0)
+	assert.Equal(t, entries[2]["level"], "ERROR")
+	assert.Equal(t, entries[3]["log.suppressed"], nil)
+}
+
+func (sampleSuite) TestDedupSlog(t *testing.T) {
+	cleanup := setupSlogTest(t)
+	defer cleanup()
+	defer log.SetSampling(log.SamplingOptions{})
+
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+
+	log.SetSampling(log.SamplingOptions{DedupWindow: 50 * time.Millisecond})
+	logLoop := func(n int) {
+		for range n {
+			log.Warn(t.Context(), "disk slow")
+		}
+	}
+	logLoop(3)
+	time.Sleep(60 * time.Millisecond)
+	logLoop(1)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Equal(t, len(lines), 3, buf.String())
+	assert.Assert(t, strings.Contains(lines[1], "log.suppressed"), lines[1])
+	assert.Assert(t, strings.Contains(lines[1], "sample_test.go"), lines[1])
+}
+
+func (sampleSuite) TestDedupSummaryWithoutNextLine(t *testing.T) {
+	logs := logtest.NewLogRecorder(t)
+	defer logs.Close()
+	defer log.SetSampling(log.SamplingOptions{})
+
+	log.SetSampling(log.SamplingOptions{DedupWindow: 20 * time.Millisecond})
+	for range 3 {
+		log.Warn(t.Context(), "cache miss")
+	}
+
+	// the call site never logs again, the summary is emitted when the
+	// window is over
+	deadline := time.Now().Add(5 * time.Second)
+	for len(logs.Entries()) < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	entries := logs.Entries()
+	assert.DeepEqual(t, messages(entries), []string{"cache miss", "cache miss"})
+	assert.Equal(t, entries[1]["log.suppressed"], 2.0)
+	assert.Equal(t, entries[1]["level"], "WARN")
+}
+
+func (sampleSuite) TestDedupSetSamplingEmitsSummaries(t *testing.T) {
+	logs := logtest.NewLogRecorder(t)
+	defer logs.Close()
+
+	log.SetSampling(log.SamplingOptions{DedupWindow: time.Hour})
+	for range 4 {
+		log.Error(t.Context(), "timeout")
+	}
+	log.SetSampling(log.SamplingOptions{})
+
+	entries := logs.Entries()
+	assert.DeepEqual(t, messages(entries), []string{"timeout", "timeout"})
+	assert.Equal(t, entries[1]["log.suppressed"], 3.0)
+}
+
+func (sampleSuite) TestDedupCap(t *testing.T) {
+	logs := logtest.NewLogRecorder(t)
+	defer logs.Close()
+	defer log.SetSampling(log.SamplingOptions{})
+
+	log.SetSampling(log.SamplingOptions{DedupWindow: time.Hour})
+	logMessage := func(i int) {
+		log.Info(t.Context(), "message "+strconv.Itoa(i))
+	}
+	for i := range 5000 {
+		logMessage(i)
+	}
+	assert.Equal(t, len(logs.Entries()), 5000)
+
+	// the first messages are deduplicated, those past the cap are not
+	logMessage(0)
+	logMessage(4999)
+	assert.Equal(t, len(logs.Entries()), 5001)
+}
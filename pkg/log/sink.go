@@ -0,0 +1,261 @@
+// Copyright 2026 Outreach Corporation. All Rights Reserved.
+
+// Description: Provides pluggable destinations for the JSON logger
+
+package log
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Entry is a log entry as given to a Sink.
+type Entry struct {
+	// Level is the level of the entry, LevelFatal for Fatal logs.
+	Level slog.Level
+
+	// JSON is the entry formatted as a single line JSON object, without a
+	// trailing newline.
+	JSON string
+}
+
+// Fields decodes the fields of the entry.
+func (e Entry) Fields() (F, error) {
+	f := F{}
+	if err := json.Unmarshal([]byte(e.JSON), &f); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Sink is a destination of the logs written outside of the slog facade.
+// By default logs are written to the Output writer; SetSink sends them to
+// a Sink instead, such as a combination of the sinks of this package:
+//
+//	file, err := log.NewRotatingFileSink("app.log", log.WithCompression())
+//	...
+//	log.SetSink(log.FanOut(
+//		file,
+//		log.FilterLevel(log.NewTextSink(os.Stderr), slog.LevelWarn),
+//	))
+//
+// Sinks must be safe for concurrent use. Sinks which hold resources also
+// implement io.Closer, and sinks which buffer entries implement Syncer.
+type Sink interface {
+	// WriteEntry writes an entry.
+	WriteEntry(e Entry) error
+}
+
+// Syncer is implemented by the sinks which buffer entries. Sync writes
+// out the buffered entries; it is called before Fatal exits.
+type Syncer interface {
+	Sync() error
+}
+
+// SinkFunc is a function implementing the Sink interface.
+type SinkFunc func(e Entry) error
+
+// WriteEntry implements the Sink interface.
+func (f SinkFunc) WriteEntry(e Entry) error {
+	return f(e)
+}
+
+// nolint:gochecknoglobals // Why: sets up overwritable sinks
+var (
+	sinkLock = new(sync.RWMutex)
+	sink     Sink
+)
+
+// SetSink sends the logs written outside of the slog facade to s rather
+// than to the Output writer, or back to the Output writer if s is nil.
+// Debug logs are written to s with slog.LevelDebug when they are flushed.
+// The previous sink is not closed.
+// Note: this function should not be used in production code outside of service startup.
+func SetSink(s Sink) {
+	sinkLock.Lock()
+	defer sinkLock.Unlock()
+
+	sink = s
+}
+
+// currentSink returns the sink set by SetSink, or nil
+func currentSink() Sink {
+	sinkLock.RLock()
+	defer sinkLock.RUnlock()
+	return sink
+}
+
+// writeEntry writes a formatted entry to the sink, or the Output writer
+func writeEntry(level slog.Level, s string) {
+	sink := currentSink()
+	if sink == nil {
+		Write(s)
+		return
+	}
+	if err := sink.WriteEntry(Entry{Level: level, JSON: s}); err != nil {
+		fmt.Fprintln(errOut, err)
+	}
+}
+
+// writeDebug writes a debug entry flushed from a debug buffer
+func writeDebug(s string) {
+	writeEntry(slog.LevelDebug, s)
+}
+
+// syncSink syncs the sink, if it buffers entries
+func syncSink() {
+	if s, ok := currentSink().(Syncer); ok {
+		if err := s.Sync(); err != nil {
+			fmt.Fprintln(errOut, err)
+		}
+	}
+}
+
+// NewWriterSink returns a Sink writing entries to w as JSON lines, as the
+// default output does.
+func NewWriterSink(w io.Writer) Sink {
+	sw := &syncWriter{w: w}
+	return SinkFunc(func(e Entry) error {
+		_, err := fmt.Fprintln(sw, e.JSON)
+		return err
+	})
+}
+
+// NewTextSink returns a Sink writing entries to w as human readable
+// lines: the time, level and message, followed by the other fields as
+// sorted key=value pairs.
+//
+//	15:04:05.000 INFO  request done duration=0.25 status=200
+func NewTextSink(w io.Writer) Sink {
+	sw := &syncWriter{w: w}
+	return SinkFunc(func(e Entry) error {
+		f, err := e.Fields()
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(sw, formatText(e.Level, f))
+		return err
+	})
+}
+
+// formatText formats the fields of an entry for NewTextSink
+func formatText(level slog.Level, f F) string {
+	var b strings.Builder
+	if ts, err := time.Parse(time.RFC3339Nano, fmt.Sprint(f["@timestamp"])); err == nil {
+		b.WriteString(ts.Local().Format("15:04:05.000 "))
+	}
+	name := level.String()
+	if level == LevelFatal {
+		name = "FATAL"
+	}
+	fmt.Fprintf(&b, "%-5s %v", name, f["message"])
+
+	keys := make([]string, 0, len(f))
+	for k := range f {
+		switch k {
+		case "@timestamp", "level", "message":
+		default:
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		v := fmt.Sprint(f[k])
+		if strings.ContainsAny(v, " \t\n\"=") {
+			v = fmt.Sprintf("%q", v)
+		}
+		fmt.Fprintf(&b, " %s=%s", k, v)
+	}
+	b.WriteByte('\n')
+	return b.String()
+}
+
+// FilterLevel returns a Sink writing to s the entries at or above the
+// given level.
+func FilterLevel(s Sink, minLevel slog.Level) Sink {
+	return &filterSink{Sink: s, min: minLevel}
+}
+
+// filterSink implements FilterLevel
+type filterSink struct {
+	Sink
+	min slog.Level
+}
+
+// WriteEntry implements the Sink interface.
+func (f *filterSink) WriteEntry(e Entry) error {
+	if e.Level < f.min {
+		return nil
+	}
+	return f.Sink.WriteEntry(e)
+}
+
+// Sync implements the Syncer interface.
+func (f *filterSink) Sync() error {
+	return syncAll(f.Sink)
+}
+
+// Close implements io.Closer.
+func (f *filterSink) Close() error {
+	return closeAll(f.Sink)
+}
+
+// FanOut returns a Sink writing every entry to all of the sinks. Its
+// Sync and Close methods sync and close the sinks which support it.
+func FanOut(sinks ...Sink) Sink {
+	return fanOutSink(sinks)
+}
+
+// fanOutSink implements FanOut
+type fanOutSink []Sink
+
+// WriteEntry implements the Sink interface. It writes to every sink, even
+// when some fail, and returns their errors joined.
+func (f fanOutSink) WriteEntry(e Entry) error {
+	var errs []error
+	for _, s := range f {
+		if err := s.WriteEntry(e); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Sync implements the Syncer interface.
+func (f fanOutSink) Sync() error {
+	return syncAll(f...)
+}
+
+// Close implements io.Closer.
+func (f fanOutSink) Close() error {
+	return closeAll(f...)
+}
+
+// syncAll syncs the sinks which implement Syncer
+func syncAll(sinks ...Sink) error {
+	var errs []error
+	for _, s := range sinks {
+		if s, ok := s.(Syncer); ok {
+			errs = append(errs, s.Sync())
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// closeAll closes the sinks which implement io.Closer
+func closeAll(sinks ...Sink) error {
+	var errs []error
+	for _, s := range sinks {
+		if c, ok := s.(io.Closer); ok {
+			errs = append(errs, c.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,122 @@
+// Copyright 2026 Outreach Corporation. All Rights Reserved.
+
+// Description: Provides a log sink writing in the background
+
+package log
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// errAsyncSinkClosed is returned when writing to a closed AsyncSink
+var errAsyncSinkClosed = errors.New("log: write to a closed AsyncSink")
+
+// AsyncSink is a Sink writing entries to another sink from a background
+// goroutine, so that logging does not wait on slow destinations. Entries
+// logged while its buffer is full are dropped and counted.
+type AsyncSink struct {
+	sink    Sink
+	entries chan Entry
+	dropped atomic.Int64
+	done    chan struct{}
+
+	// mu guards closed and pending, idle is signaled when pending drops
+	// to zero
+	mu      sync.Mutex
+	idle    *sync.Cond
+	closed  bool
+	pending int
+}
+
+// defaultAsyncSinkSize is the buffer size of an AsyncSink created with a
+// non-positive size
+const defaultAsyncSinkSize = 1024
+
+// NewAsyncSink returns an AsyncSink writing to s with a buffer of size
+// entries, or defaultAsyncSinkSize (1024) entries if size is not
+// positive. It must be closed to stop its goroutine.
+func NewAsyncSink(s Sink, size int) *AsyncSink {
+	if size <= 0 {
+		size = defaultAsyncSinkSize
+	}
+	a := &AsyncSink{
+		sink:    s,
+		entries: make(chan Entry, size),
+		done:    make(chan struct{}),
+	}
+	a.idle = sync.NewCond(&a.mu)
+	go a.run()
+	return a
+}
+
+// run writes the queued entries until the sink is closed
+func (a *AsyncSink) run() {
+	defer close(a.done)
+	for e := range a.entries {
+		if err := a.sink.WriteEntry(e); err != nil {
+			fmt.Fprintln(errOut, err)
+		}
+
+		a.mu.Lock()
+		a.pending--
+		if a.pending == 0 {
+			a.idle.Broadcast()
+		}
+		a.mu.Unlock()
+	}
+}
+
+// WriteEntry implements the Sink interface. It queues the entry, or drops
+// it if the buffer is full.
+func (a *AsyncSink) WriteEntry(e Entry) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.closed {
+		return errAsyncSinkClosed
+	}
+	select {
+	case a.entries <- e:
+		a.pending++
+	default:
+		a.dropped.Add(1)
+	}
+	return nil
+}
+
+// Dropped returns the number of entries dropped because the buffer was
+// full.
+func (a *AsyncSink) Dropped() int64 {
+	return a.dropped.Load()
+}
+
+// Sync implements the Syncer interface. It waits for the queued entries to
+// be written, then syncs the underlying sink.
+func (a *AsyncSink) Sync() error {
+	a.mu.Lock()
+	for a.pending > 0 {
+		a.idle.Wait()
+	}
+	a.mu.Unlock()
+
+	return syncAll(a.sink)
+}
+
+// Close implements io.Closer. It writes the queued entries, then closes
+// the underlying sink if it is an io.Closer.
+func (a *AsyncSink) Close() error {
+	a.mu.Lock()
+	if a.closed {
+		a.mu.Unlock()
+		return nil
+	}
+	a.closed = true
+	close(a.entries)
+	a.mu.Unlock()
+
+	<-a.done
+	return closeAll(a.sink)
+}
@@ -0,0 +1,309 @@
+// Copyright 2026 Outreach Corporation. All Rights Reserved.
+
+// Description: Provides a log sink writing to rotated files
+
+package log
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultMaxSize is the default size at which a RotatingFileSink rotates
+const defaultMaxSize = 100 * 1024 * 1024
+
+// rotateTimeFormat is the suffix of rotated files, which sorts by time
+const rotateTimeFormat = "20060102T150405.000000000"
+
+// FileSinkOptions are the options of a RotatingFileSink.
+type FileSinkOptions struct {
+	// MaxSize is the size in bytes past which the file is rotated, 0 or
+	// less for the default of 100MB.
+	MaxSize int64
+
+	// MaxAge is the age past which the file is rotated, 0 to never
+	// rotate on age.
+	MaxAge time.Duration
+
+	// MaxBackups is the number of rotated files to keep, 0 to keep all.
+	MaxBackups int
+
+	// Compress gzips rotated files.
+	Compress bool
+}
+
+// FileSinkOption sets an option of a RotatingFileSink.
+type FileSinkOption func(*FileSinkOptions)
+
+// WithMaxSize sets the size in bytes past which the file is rotated, 100MB
+// by default or if size is 0 or less.
+func WithMaxSize(size int64) FileSinkOption {
+	return func(o *FileSinkOptions) {
+		o.MaxSize = size
+	}
+}
+
+// WithMaxAge rotates the file once it is older than age.
+func WithMaxAge(age time.Duration) FileSinkOption {
+	return func(o *FileSinkOptions) {
+		o.MaxAge = age
+	}
+}
+
+// WithMaxBackups sets the number of rotated files to keep, the older ones
+// are removed.
+func WithMaxBackups(n int) FileSinkOption {
+	return func(o *FileSinkOptions) {
+		o.MaxBackups = n
+	}
+}
+
+// WithCompression gzips the rotated files.
+func WithCompression() FileSinkOption {
+	return func(o *FileSinkOptions) {
+		o.Compress = true
+	}
+}
+
+// RotatingFileSink is a Sink writing entries as JSON lines to a file,
+// which is rotated once it is too large or too old. Rotated files are
+// renamed to the path followed by the time of the rotation, such as
+// app.log.20260102T150405.000000000, and gzipped in the background if
+// compression is enabled.
+type RotatingFileSink struct {
+	path string
+	opts FileSinkOptions
+
+	mu      sync.Mutex
+	f       *os.File
+	size    int64
+	created time.Time
+
+	// finishLock serializes the compression and pruning of rotated files,
+	// running counts them and is guarded by runningLock
+	finishLock  sync.Mutex
+	runningLock sync.Mutex
+	finished    *sync.Cond
+	running     int
+}
+
+// NewRotatingFileSink returns a RotatingFileSink appending to the file
+// at path, which is created if needed.
+func NewRotatingFileSink(path string, opts ...FileSinkOption) (*RotatingFileSink, error) {
+	s := &RotatingFileSink{path: path, opts: FileSinkOptions{MaxSize: defaultMaxSize}}
+	s.finished = sync.NewCond(&s.runningLock)
+	for _, opt := range opts {
+		opt(&s.opts)
+	}
+	if s.opts.MaxSize <= 0 {
+		s.opts.MaxSize = defaultMaxSize
+	}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// open opens the file, the lock must be held
+func (s *RotatingFileSink) open() error {
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close() //nolint:errcheck // Why: best effort
+		return err
+	}
+	s.f = f
+	s.size = info.Size()
+	s.created = time.Now()
+	if s.size > 0 {
+		// the file was already there, age it from its last write
+		s.created = info.ModTime()
+	}
+	return nil
+}
+
+// WriteEntry implements the Sink interface.
+func (s *RotatingFileSink) WriteEntry(e Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.f == nil {
+		return os.ErrClosed
+	}
+
+	n := int64(len(e.JSON) + 1)
+	if s.size > 0 && (s.size+n > s.opts.MaxSize || (s.opts.MaxAge > 0 && time.Since(s.created) > s.opts.MaxAge)) {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+	}
+
+	written, err := io.WriteString(s.f, e.JSON+"\n")
+	s.size += int64(written)
+	return err
+}
+
+// Rotate rotates the file now.
+func (s *RotatingFileSink) Rotate() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.f == nil {
+		return os.ErrClosed
+	}
+	return s.rotate()
+}
+
+// rotate renames the file and opens a new one, the lock must be held
+func (s *RotatingFileSink) rotate() error {
+	if err := s.f.Close(); err != nil {
+		return err
+	}
+	s.f = nil
+
+	rotated := s.path + "." + time.Now().UTC().Format(rotateTimeFormat)
+	if err := os.Rename(s.path, rotated); err != nil {
+		return errors.Join(err, s.open())
+	}
+	if err := s.open(); err != nil {
+		return err
+	}
+
+	s.runningLock.Lock()
+	s.running++
+	s.runningLock.Unlock()
+	go s.finish(rotated)
+	return nil
+}
+
+// finish compresses a rotated file and removes the old backups
+func (s *RotatingFileSink) finish(rotated string) {
+	defer func() {
+		s.runningLock.Lock()
+		s.running--
+		s.finished.Broadcast()
+		s.runningLock.Unlock()
+	}()
+
+	s.finishLock.Lock()
+	defer s.finishLock.Unlock()
+
+	// rotated may already be pruned by the finish of a later rotation
+	if s.opts.Compress {
+		if err := compressFile(rotated); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			fmt.Fprintln(errOut, "failed to compress log file:", err)
+		}
+	}
+
+	if err := s.prune(); err != nil {
+		fmt.Fprintln(errOut, "failed to remove old log files:", err)
+	}
+}
+
+// prune removes the backups past MaxBackups
+func (s *RotatingFileSink) prune() error {
+	if s.opts.MaxBackups <= 0 {
+		return nil
+	}
+
+	backups, err := s.Backups()
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for len(backups) > s.opts.MaxBackups {
+		errs = append(errs, os.Remove(backups[0]))
+		backups = backups[1:]
+	}
+	return errors.Join(errs...)
+}
+
+// Backups returns the paths of the rotated files, oldest first.
+func (s *RotatingFileSink) Backups() ([]string, error) {
+	matches, err := filepath.Glob(s.path + ".*")
+	if err != nil {
+		return nil, err
+	}
+
+	backups := make([]string, 0, len(matches))
+	for _, m := range matches {
+		stamp := strings.TrimSuffix(strings.TrimPrefix(m, s.path+"."), ".gz")
+		if _, err := time.Parse(rotateTimeFormat, stamp); err == nil {
+			backups = append(backups, m)
+		}
+	}
+	sort.Strings(backups)
+	return backups, nil
+}
+
+// compressFile gzips path into path.gz and removes path
+func compressFile(path string) error {
+	in, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(out)
+	_, err = io.Copy(gz, in)
+	if err = errors.Join(err, gz.Close(), out.Close()); err != nil {
+		os.Remove(path + ".gz") //nolint:errcheck // Why: best effort
+		return err
+	}
+	return os.Remove(path)
+}
+
+// Sync implements the Syncer interface. It commits the file to disk and
+// waits for the rotated files to be compressed.
+func (s *RotatingFileSink) Sync() error {
+	s.mu.Lock()
+	var err error
+	if s.f != nil {
+		err = s.f.Sync()
+	}
+	s.mu.Unlock()
+
+	s.wait()
+	return err
+}
+
+// wait waits for the rotated files to be compressed and pruned
+func (s *RotatingFileSink) wait() {
+	s.runningLock.Lock()
+	defer s.runningLock.Unlock()
+	for s.running > 0 {
+		s.finished.Wait()
+	}
+}
+
+// Close implements io.Closer. It closes the file and waits for the rotated
+// files to be compressed.
+func (s *RotatingFileSink) Close() error {
+	s.mu.Lock()
+	var err error
+	if s.f != nil {
+		err = s.f.Close()
+		s.f = nil
+	}
+	s.mu.Unlock()
+
+	s.wait()
+	return err
+}
@@ -0,0 +1,135 @@
+// Copyright 2026 Outreach Corporation. All Rights Reserved.
+
+// Description: Provides a log sink writing to the local syslog or journald
+
+package log
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// syslogFacilityUser is the syslog facility of the entries
+const syslogFacilityUser = 1
+
+// errSyslogSinkClosed is returned when writing to a closed SyslogSink
+var errSyslogSinkClosed = errors.New("log: write to a closed SyslogSink")
+
+// syslogSockets are the local syslog sockets, journald listens on
+// /dev/log on systemd hosts
+// nolint:gochecknoglobals // Why: constant list of paths
+var syslogSockets = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+// SyslogSink is a Sink writing entries to a syslog daemon, or journald,
+// in the BSD syslog format with the JSON entry as the message.
+type SyslogSink struct {
+	network, addr, tag string
+
+	mu     sync.Mutex
+	conn   net.Conn
+	closed bool
+}
+
+// NewSyslogSink returns a SyslogSink sending entries tagged with tag, or
+// the program name if empty, to the syslog daemon listening at addr on
+// network, such as "udp" and "localhost:514". If addr is empty it
+// connects to the local syslog socket, which is journald on systemd hosts.
+func NewSyslogSink(network, addr, tag string) (*SyslogSink, error) {
+	if tag == "" {
+		tag = filepath.Base(os.Args[0])
+	}
+	s := &SyslogSink{network: network, addr: addr, tag: tag}
+	if err := s.connect(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// connect connects to the syslog daemon, the lock must be held
+func (s *SyslogSink) connect() error {
+	if s.addr != "" {
+		conn, err := net.Dial(s.network, s.addr)
+		if err != nil {
+			return err
+		}
+		s.conn = conn
+		return nil
+	}
+
+	var errs []error
+	for _, network := range []string{"unixgram", "unix"} {
+		for _, path := range syslogSockets {
+			conn, err := net.Dial(network, path)
+			if err == nil {
+				s.conn = conn
+				return nil
+			}
+			errs = append(errs, err)
+		}
+	}
+	return fmt.Errorf("log: no local syslog socket: %w", errors.Join(errs...))
+}
+
+// WriteEntry implements the Sink interface. It reconnects once if the
+// write fails, as when the daemon restarted. It fails once the sink is
+// closed.
+func (s *SyslogSink) WriteEntry(e Entry) error {
+	msg := fmt.Sprintf("<%d>%s %s[%d]: %s\n",
+		syslogFacilityUser*8+syslogSeverity(e.Level),
+		time.Now().Format(time.Stamp), s.tag, os.Getpid(), e.JSON)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return errSyslogSinkClosed
+	}
+	if s.conn != nil {
+		if _, err := s.conn.Write([]byte(msg)); err == nil {
+			return nil
+		}
+		s.conn.Close() //nolint:errcheck // Why: reconnecting
+		s.conn = nil
+	}
+	if err := s.connect(); err != nil {
+		return err
+	}
+	_, err := s.conn.Write([]byte(msg))
+	return err
+}
+
+// Close implements io.Closer.
+func (s *SyslogSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+// syslogSeverity maps a level to a syslog severity
+func syslogSeverity(level slog.Level) int {
+	switch {
+	case level >= LevelFatal:
+		return 2 // critical
+	case level >= slog.LevelError:
+		return 3 // error
+	case level >= slog.LevelWarn:
+		return 4 // warning
+	case level >= slog.LevelInfo:
+		return 6 // informational
+	default:
+		return 7 // debug
+	}
+}
@@ -0,0 +1,230 @@
+//go:build !or_e2e
+
+package log_test
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/getoutreach/gobox/pkg/log"
+	"gotest.tools/v3/assert"
+)
+
+type sinkSuite struct{}
+
+// recordSink records the entries written to it
+type recordSink struct {
+	mu      sync.Mutex
+	entries []log.Entry
+
+	// started receives each entry before block is waited on
+	started chan struct{}
+	block   chan struct{}
+}
+
+func (r *recordSink) WriteEntry(e log.Entry) error {
+	if r.block != nil {
+		r.started <- struct{}{}
+		<-r.block
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, e)
+	return nil
+}
+
+func (r *recordSink) Messages() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var msgs []string
+	for _, e := range r.entries {
+		f, err := e.Fields()
+		if err != nil {
+			panic(err)
+		}
+		msgs = append(msgs, f["message"].(string))
+	}
+	return msgs
+}
+
+func (sinkSuite) TestFanOutAndFilter(t *testing.T) {
+	var all recordSink
+	var text bytes.Buffer
+	log.SetSink(log.FanOut(&all, log.FilterLevel(log.NewTextSink(&text), slog.LevelWarn)))
+	defer log.SetSink(nil)
+	defer log.Purge(t.Context())
+
+	log.Debug(t.Context(), "debug")
+	log.Info(t.Context(), "info", log.F{"count": 1})
+	log.Warn(t.Context(), "warn", log.F{"reason": "slow disk"})
+	log.Error(t.Context(), "error")
+
+	assert.DeepEqual(t, all.Messages(), []string{"info", "warn", "debug", "error"})
+	assert.Equal(t, all.entries[2].Level, slog.LevelDebug)
+
+	lines := strings.Split(strings.TrimSpace(text.String()), "\n")
+	assert.Equal(t, len(lines), 2)
+	assert.Assert(t, strings.Contains(lines[0], `WARN  warn `), lines[0])
+	assert.Assert(t, strings.Contains(lines[0], `reason="slow disk"`), lines[0])
+	assert.Assert(t, strings.Contains(lines[1], `ERROR error `), lines[1])
+}
+
+func (sinkSuite) TestWriterSink(t *testing.T) {
+	var out bytes.Buffer
+	log.SetSink(log.NewWriterSink(&out))
+	defer log.SetSink(nil)
+
+	log.Info(t.Context(), "hello")
+	assert.Assert(t, strings.HasSuffix(out.String(), "\n"))
+	f, err := log.Entry{JSON: strings.TrimSpace(out.String())}.Fields()
+	assert.NilError(t, err)
+	assert.Equal(t, f["message"], "hello")
+}
+
+func (sinkSuite) TestRotatingFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	line := `{"message":"` + strings.Repeat("x", 80) + `"}`
+
+	s, err := log.NewRotatingFileSink(path, log.WithMaxSize(200), log.WithMaxBackups(2), log.WithCompression())
+	assert.NilError(t, err)
+	for range 8 {
+		assert.NilError(t, s.WriteEntry(log.Entry{Level: slog.LevelInfo, JSON: line}))
+	}
+	assert.NilError(t, s.Close())
+
+	// 2 lines per file, 4 files of which 2 backups are kept
+	backups, err := s.Backups()
+	assert.NilError(t, err)
+	assert.Equal(t, len(backups), 2)
+	for _, b := range backups {
+		assert.Assert(t, strings.HasSuffix(b, ".gz"), b)
+		f, err := os.Open(b)
+		assert.NilError(t, err)
+		gz, err := gzip.NewReader(f)
+		assert.NilError(t, err)
+		data, err := io.ReadAll(gz)
+		assert.NilError(t, err)
+		f.Close()
+		assert.Equal(t, string(data), line+"\n"+line+"\n")
+	}
+
+	data, err := os.ReadFile(path)
+	assert.NilError(t, err)
+	assert.Equal(t, string(data), line+"\n"+line+"\n")
+
+	assert.ErrorIs(t, s.WriteEntry(log.Entry{JSON: line}), os.ErrClosed)
+}
+
+func (sinkSuite) TestRotatingFileSinkMaxAge(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	s, err := log.NewRotatingFileSink(path, log.WithMaxAge(time.Millisecond))
+	assert.NilError(t, err)
+	defer s.Close()
+
+	assert.NilError(t, s.WriteEntry(log.Entry{JSON: "{}"}))
+	time.Sleep(5 * time.Millisecond)
+	assert.NilError(t, s.WriteEntry(log.Entry{JSON: "{}"}))
+	assert.NilError(t, s.Sync())
+
+	backups, err := s.Backups()
+	assert.NilError(t, err)
+	assert.Equal(t, len(backups), 1)
+}
+
+func (sinkSuite) TestRotatingFileSinkZeroMaxSize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	s, err := log.NewRotatingFileSink(path, log.WithMaxSize(0))
+	assert.NilError(t, err)
+	defer s.Close()
+
+	// the default size applies, nothing is rotated
+	for range 3 {
+		assert.NilError(t, s.WriteEntry(log.Entry{JSON: "{}"}))
+	}
+	assert.NilError(t, s.Sync())
+
+	backups, err := s.Backups()
+	assert.NilError(t, err)
+	assert.Equal(t, len(backups), 0)
+}
+
+func (sinkSuite) TestAsyncSinkDrops(t *testing.T) {
+	inner := &recordSink{started: make(chan struct{}, 10), block: make(chan struct{})}
+	s := log.NewAsyncSink(inner, 2)
+
+	// the first entry blocks the goroutine, the next two fill the buffer
+	assert.NilError(t, s.WriteEntry(log.Entry{JSON: `{"message":"0"}`}))
+	<-inner.started
+	for i := 1; i <= 5; i++ {
+		assert.NilError(t, s.WriteEntry(log.Entry{JSON: `{"message":"` + string(rune('0'+i)) + `"}`}))
+	}
+	assert.Equal(t, s.Dropped(), int64(3))
+
+	close(inner.block)
+	assert.NilError(t, s.Sync())
+	assert.DeepEqual(t, inner.Messages(), []string{"0", "1", "2"})
+
+	assert.NilError(t, s.Close())
+	assert.Assert(t, s.WriteEntry(log.Entry{JSON: "{}"}) != nil)
+}
+
+func (sinkSuite) TestAsyncSinkNonPositiveSize(t *testing.T) {
+	for _, size := range []int{0, -1} {
+		inner := &recordSink{started: make(chan struct{}, 200), block: make(chan struct{})}
+		s := log.NewAsyncSink(inner, size)
+
+		// the default buffer holds the entries while the goroutine is
+		// blocked
+		assert.NilError(t, s.WriteEntry(log.Entry{JSON: `{"message":"0"}`}))
+		<-inner.started
+		for range 100 {
+			assert.NilError(t, s.WriteEntry(log.Entry{JSON: `{"message":"1"}`}))
+		}
+		assert.Equal(t, s.Dropped(), int64(0))
+
+		close(inner.block)
+		assert.NilError(t, s.Close())
+		assert.Equal(t, len(inner.Messages()), 101)
+	}
+}
+
+func (sinkSuite) TestSyslogSink(t *testing.T) {
+	// unix socket paths are limited to ~100 bytes, t.TempDir may be longer
+	dir, err := os.MkdirTemp("", "syslog")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+	addr := filepath.Join(dir, "log")
+
+	conn, err := net.ListenPacket("unixgram", addr)
+	assert.NilError(t, err)
+	defer conn.Close()
+
+	s, err := log.NewSyslogSink("unixgram", addr, "myapp")
+	assert.NilError(t, err)
+	defer s.Close()
+
+	assert.NilError(t, s.WriteEntry(log.Entry{Level: slog.LevelWarn, JSON: `{"message":"hi"}`}))
+
+	buf := make([]byte, 1024)
+	n, _, err := conn.ReadFrom(buf)
+	assert.NilError(t, err)
+	msg, err := bufio.NewReader(bytes.NewReader(buf[:n])).ReadString('\n')
+	assert.NilError(t, err)
+	assert.Assert(t, strings.HasPrefix(msg, "<12>"), msg)
+	assert.Assert(t, strings.HasSuffix(msg, " myapp["+strconv.Itoa(os.Getpid())+`]: {"message":"hi"}`+"\n"), msg)
+
+	// a closed sink does not reconnect
+	assert.NilError(t, s.Close())
+	assert.Assert(t, s.WriteEntry(log.Entry{JSON: "{}"}) != nil)
+}
@@ -0,0 +1,151 @@
+// Copyright 2026 Outreach Corporation. All Rights Reserved.
+
+// Description: Implements deep merging of nested maps.
+
+package maps
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// ConflictFunc resolves a conflict found by DeepMerge: path is the key
+// path of the conflicting values, dst the value in the first map and src
+// the value in the second one. It returns the merged value.
+//
+// Values conflict when the key exists in both maps with different values,
+// unless both are map[string]any, which are merged recursively.
+type ConflictFunc func(path []string, dst, src any) (any, error)
+
+// Overwrite is a ConflictFunc which keeps the value of the second map, as
+// Merge does with overwrite. This is the default of DeepMerge.
+func Overwrite(_ []string, _, src any) (any, error) {
+	return src, nil
+}
+
+// Keep is a ConflictFunc which keeps the value of the first map, as Merge
+// does without overwrite.
+func Keep(_ []string, dst, _ any) (any, error) {
+	return dst, nil
+}
+
+// Reject is a ConflictFunc which fails with a *ConflictError.
+func Reject(path []string, dst, src any) (any, error) {
+	return nil, &ConflictError{Path: path, Dst: dst, Src: src}
+}
+
+// AppendSlices is a ConflictFunc which concatenates conflicting []any
+// values and otherwise keeps the value of the second map.
+func AppendSlices(_ []string, dst, src any) (any, error) {
+	a, aok := dst.([]any)
+	b, bok := src.([]any)
+	if aok && bok {
+		return slices.Concat(a, b), nil
+	}
+	return src, nil
+}
+
+// ConflictError is returned by DeepMerge when the Reject ConflictFunc is
+// used and the maps have conflicting values.
+type ConflictError struct {
+	// Path is the key path of the conflicting values.
+	Path []string
+
+	// Dst and Src are the conflicting values of the first and second map.
+	Dst, Src any
+}
+
+// Error implements the error interface.
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("conflicting values at %q: %v and %v", strings.Join(e.Path, "."), e.Dst, e.Src)
+}
+
+// MergeOption is used to change the behavior of DeepMerge.
+type MergeOption func(o *mergeOptions)
+
+// mergeOptions holds the options of DeepMerge.
+type mergeOptions struct {
+	conflict ConflictFunc
+}
+
+// WithConflictFunc sets how DeepMerge resolves conflicting values.
+// Defaults to Overwrite.
+func WithConflictFunc(fn ConflictFunc) MergeOption {
+	return func(o *mergeOptions) {
+		o.conflict = fn
+	}
+}
+
+// DeepMerge merges the nested maps a and b, such as decoded JSON or YAML
+// documents, and returns the result. Nested map[string]any values present
+// in both maps are merged recursively; other values present in both maps
+// with different values are resolved with the ConflictFunc, which
+// defaults to Overwrite, b winning like with Merge(a, b, true).
+//
+// a and b are not modified, and the result does not share nested maps or
+// []any values with them.
+func DeepMerge(a, b map[string]any, opts ...MergeOption) (map[string]any, error) {
+	o := mergeOptions{conflict: Overwrite}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return deepMerge(nil, a, b, o.conflict)
+}
+
+// deepMerge merges b into a copy of a, path being the key path of a and b
+func deepMerge(path []string, a, b map[string]any, conflict ConflictFunc) (map[string]any, error) {
+	result := make(map[string]any, max(len(a), len(b)))
+	for k, v := range a {
+		result[k] = deepCopy(v)
+	}
+	for k, src := range b {
+		dst, ok := a[k]
+		if !ok {
+			result[k] = deepCopy(src)
+			continue
+		}
+
+		path := append(slices.Clip(path), k)
+		dstMap, dstIsMap := dst.(map[string]any)
+		srcMap, srcIsMap := src.(map[string]any)
+		switch {
+		case dstIsMap && srcIsMap:
+			merged, err := deepMerge(path, dstMap, srcMap, conflict)
+			if err != nil {
+				return nil, err
+			}
+			result[k] = merged
+		case reflect.DeepEqual(dst, src):
+			// not a conflict, the copy of dst is kept
+		default:
+			v, err := conflict(path, dst, src)
+			if err != nil {
+				return nil, err
+			}
+			result[k] = deepCopy(v)
+		}
+	}
+	return result, nil
+}
+
+// deepCopy copies the nested map[string]any and []any of v
+func deepCopy(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		m := make(map[string]any, len(v))
+		for k, e := range v {
+			m[k] = deepCopy(e)
+		}
+		return m
+	case []any:
+		s := make([]any, len(v))
+		for i, e := range v {
+			s[i] = deepCopy(e)
+		}
+		return s
+	default:
+		return v
+	}
+}
@@ -0,0 +1,69 @@
+package maps
+
+import (
+	"errors"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestDeepMerge(t *testing.T) {
+	a := map[string]any{
+		"name": "app",
+		"server": map[string]any{
+			"port": 80,
+			"tls":  map[string]any{"enabled": false},
+		},
+		"tags": []any{"a"},
+	}
+	b := map[string]any{
+		"server": map[string]any{
+			"port": 443,
+			"tls":  map[string]any{"cert": "cert.pem"},
+		},
+		"tags":  []any{"b"},
+		"debug": true,
+	}
+
+	r, err := DeepMerge(a, b)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, r, map[string]any{
+		"name": "app",
+		"server": map[string]any{
+			"port": 443,
+			"tls":  map[string]any{"enabled": false, "cert": "cert.pem"},
+		},
+		"tags":  []any{"b"},
+		"debug": true,
+	})
+
+	// the result does not share nested maps with the inputs
+	r["server"].(map[string]any)["port"] = 8080
+	assert.Equal(t, a["server"].(map[string]any)["port"], 80)
+	assert.Equal(t, b["server"].(map[string]any)["port"], 443)
+
+	r, err = DeepMerge(a, b, WithConflictFunc(Keep))
+	assert.NilError(t, err)
+	assert.Equal(t, r["server"].(map[string]any)["port"], 80)
+	assert.DeepEqual(t, r["tags"], []any{"a"})
+
+	r, err = DeepMerge(a, b, WithConflictFunc(AppendSlices))
+	assert.NilError(t, err)
+	assert.Equal(t, r["server"].(map[string]any)["port"], 443)
+	assert.DeepEqual(t, r["tags"], []any{"a", "b"})
+}
+
+func TestDeepMergeReject(t *testing.T) {
+	a := map[string]any{"server": map[string]any{"port": 80, "host": "localhost"}}
+	b := map[string]any{"server": map[string]any{"port": 443, "host": "localhost"}}
+
+	_, err := DeepMerge(a, b, WithConflictFunc(Reject))
+	var conflictErr *ConflictError
+	assert.Assert(t, errors.As(err, &conflictErr))
+	assert.DeepEqual(t, conflictErr.Path, []string{"server", "port"})
+	assert.Error(t, err, `conflicting values at "server.port": 80 and 443`)
+
+	// equal values do not conflict
+	_, err = DeepMerge(a, a, WithConflictFunc(Reject))
+	assert.NilError(t, err)
+}
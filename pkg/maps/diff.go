@@ -0,0 +1,117 @@
+// Copyright 2026 Outreach Corporation. All Rights Reserved.
+
+// Description: Implements deep diffing of nested maps.
+
+package maps
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Change is a value changed between the maps compared by DeepDiff.
+type Change struct {
+	From any
+	To   any
+}
+
+// Diff is the difference between two nested maps, as returned by
+// DeepDiff. Keys are the dot separated key paths of the values, such as
+// "server.tls.enabled". Fields with no differences are nil, so that a Diff
+// can be compared with an expected one, including differs comparers:
+//
+//	diff := maps.DeepDiff(before, after)
+//	expected := maps.Diff{
+//		Changed: map[string]maps.Change{
+//			"updated_at": {From: differs.RFC3339Time(), To: differs.RFC3339Time()},
+//		},
+//	}
+//	if d := cmp.Diff(expected, diff, differs.Custom()); d != "" {
+//		t.Fatal(d)
+//	}
+type Diff struct {
+	// Added holds the values only in the second map.
+	Added map[string]any
+
+	// Removed holds the values only in the first map.
+	Removed map[string]any
+
+	// Changed holds the values which differ between the maps.
+	Changed map[string]Change
+}
+
+// Empty reports whether the maps are equal.
+func (d Diff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// String returns a line per difference, sorted by key path, in the form
+// "+ path: value", "- path: value" or "~ path: from -> to".
+func (d Diff) String() string {
+	type line struct{ path, text string }
+	lines := make([]line, 0, len(d.Added)+len(d.Removed)+len(d.Changed))
+	for p, v := range d.Added {
+		lines = append(lines, line{p, fmt.Sprintf("+ %s: %v", p, v)})
+	}
+	for p, v := range d.Removed {
+		lines = append(lines, line{p, fmt.Sprintf("- %s: %v", p, v)})
+	}
+	for p, c := range d.Changed {
+		lines = append(lines, line{p, fmt.Sprintf("~ %s: %v -> %v", p, c.From, c.To)})
+	}
+	slices.SortFunc(lines, func(a, b line) int { return strings.Compare(a.path, b.path) })
+
+	var sb strings.Builder
+	for _, l := range lines {
+		sb.WriteString(l.text)
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// DeepDiff compares the nested maps a and b, such as decoded JSON or YAML
+// documents. Nested map[string]any values are compared key by key; other
+// values are compared with reflect.DeepEqual.
+func DeepDiff(a, b map[string]any) Diff {
+	var d Diff
+	d.diff("", a, b)
+	return d
+}
+
+// diff records the differences between a and b, prefix being their key
+// path followed by a dot
+func (d *Diff) diff(prefix string, a, b map[string]any) {
+	for k, av := range a {
+		path := prefix + k
+		bv, ok := b[k]
+		if !ok {
+			if d.Removed == nil {
+				d.Removed = make(map[string]any)
+			}
+			d.Removed[path] = av
+			continue
+		}
+
+		am, aIsMap := av.(map[string]any)
+		bm, bIsMap := bv.(map[string]any)
+		switch {
+		case aIsMap && bIsMap:
+			d.diff(path+".", am, bm)
+		case !reflect.DeepEqual(av, bv):
+			if d.Changed == nil {
+				d.Changed = make(map[string]Change)
+			}
+			d.Changed[path] = Change{From: av, To: bv}
+		}
+	}
+	for k, bv := range b {
+		if _, ok := a[k]; !ok {
+			if d.Added == nil {
+				d.Added = make(map[string]any)
+			}
+			d.Added[prefix+k] = bv
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package maps
+
+import (
+	"testing"
+	"time"
+
+	"github.com/getoutreach/gobox/pkg/differs"
+	"github.com/google/go-cmp/cmp"
+	"gotest.tools/v3/assert"
+)
+
+func TestDeepDiff(t *testing.T) {
+	before := map[string]any{
+		"name":       "app",
+		"updated_at": "2026-01-01T00:00:00Z",
+		"server":     map[string]any{"port": 80, "tls": map[string]any{"enabled": false}},
+		"legacy":     true,
+	}
+	after := map[string]any{
+		"name":       "app",
+		"updated_at": time.Now().Format(time.RFC3339),
+		"server":     map[string]any{"port": 443, "tls": map[string]any{"enabled": false, "cert": "cert.pem"}},
+		"tags":       []any{"a"},
+	}
+
+	diff := DeepDiff(before, after)
+	expected := Diff{
+		Added:   map[string]any{"server.tls.cert": "cert.pem", "tags": []any{"a"}},
+		Removed: map[string]any{"legacy": true},
+		Changed: map[string]Change{
+			"server.port": {From: 80, To: 443},
+			"updated_at":  {From: differs.RFC3339Time(), To: differs.RFC3339Time()},
+		},
+	}
+	assert.DeepEqual(t, expected, diff, differs.Custom())
+	assert.Assert(t, !diff.Empty())
+
+	assert.DeepEqual(t, DeepDiff(before, before), Diff{})
+	assert.Assert(t, DeepDiff(before, before).Empty())
+}
+
+func TestDiffString(t *testing.T) {
+	diff := DeepDiff(
+		map[string]any{"a": 1, "b": map[string]any{"c": 2}},
+		map[string]any{"b": map[string]any{"c": 3}, "d": "x"},
+	)
+	assert.Equal(t, diff.String(), "- a: 1\n~ b.c: 2 -> 3\n+ d: x\n")
+	assert.Equal(t, cmp.Diff(Diff{}, DeepDiff(nil, map[string]any{})), "")
+}
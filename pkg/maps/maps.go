@@ -4,6 +4,11 @@
 
 // Package maps provides a bunch of functions to work with maps
 // This is originally intended to remove repeated code such as merging maps
+//
+// Merge merges flat maps, DeepMerge merges nested map[string]any such as
+// decoded configuration files, and DeepDiff reports how two of them differ.
+// Filter, MapValues, Invert, GroupBy and Partition work on the iter.Seq2
+// returned by the standard maps.All, to be collected with maps.Collect.
 package maps
 
 import "maps"
@@ -0,0 +1,78 @@
+// Copyright 2026 Outreach Corporation. All Rights Reserved.
+
+// Description: Implements generic operations over key-value sequences.
+
+package maps
+
+import (
+	"iter"
+)
+
+// Filter returns a sequence of the pairs of seq for which keep returns
+// true. Use maps.All to filter a map, and maps.Collect to build one:
+//
+//	active := maps.Collect(Filter(maps.All(users), func(_ string, u User) bool {
+//		return u.Active
+//	}))
+func Filter[K, V any](seq iter.Seq2[K, V], keep func(K, V) bool) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for k, v := range seq {
+			if keep(k, v) && !yield(k, v) {
+				return
+			}
+		}
+	}
+}
+
+// MapValues returns a sequence of the pairs of seq with their values
+// transformed by f.
+func MapValues[K, V, W any](seq iter.Seq2[K, V], f func(V) W) iter.Seq2[K, W] {
+	return func(yield func(K, W) bool) {
+		for k, v := range seq {
+			if !yield(k, f(v)) {
+				return
+			}
+		}
+	}
+}
+
+// Invert returns a sequence of the pairs of seq with keys and values
+// swapped. When several keys have the same value, maps.Collect keeps the
+// last one.
+func Invert[K, V any](seq iter.Seq2[K, V]) iter.Seq2[V, K] {
+	return func(yield func(V, K) bool) {
+		for k, v := range seq {
+			if !yield(v, k) {
+				return
+			}
+		}
+	}
+}
+
+// GroupBy splits the pairs of seq into maps keyed by the group returned
+// by group.
+func GroupBy[K comparable, V any, G comparable](seq iter.Seq2[K, V], group func(K, V) G) map[G]map[K]V {
+	result := make(map[G]map[K]V)
+	for k, v := range seq {
+		g := group(k, v)
+		if result[g] == nil {
+			result[g] = make(map[K]V)
+		}
+		result[g][k] = v
+	}
+	return result
+}
+
+// Partition splits the pairs of seq into those for which pred returns
+// true and the others.
+func Partition[K comparable, V any](seq iter.Seq2[K, V], pred func(K, V) bool) (matched, rest map[K]V) {
+	matched, rest = make(map[K]V), make(map[K]V)
+	for k, v := range seq {
+		if pred(k, v) {
+			matched[k] = v
+		} else {
+			rest[k] = v
+		}
+	}
+	return matched, rest
+}
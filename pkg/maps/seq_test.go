@@ -0,0 +1,42 @@
+package maps
+
+import (
+	"maps"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestSeq(t *testing.T) {
+	m := map[string]int{"one": 1, "two": 2, "three": 3, "four": 4}
+	even := func(_ string, v int) bool { return v%2 == 0 }
+
+	assert.DeepEqual(t, maps.Collect(Filter(maps.All(m), even)), map[string]int{"two": 2, "four": 4})
+	assert.DeepEqual(t, maps.Collect(MapValues(maps.All(m), func(v int) int { return v * 10 })),
+		map[string]int{"one": 10, "two": 20, "three": 30, "four": 40})
+	assert.DeepEqual(t, maps.Collect(Invert(maps.All(m))),
+		map[int]string{1: "one", 2: "two", 3: "three", 4: "four"})
+
+	byLen := GroupBy(maps.All(m), func(k string, _ int) int { return len(k) })
+	assert.DeepEqual(t, byLen, map[int]map[string]int{
+		3: {"one": 1, "two": 2},
+		4: {"four": 4},
+		5: {"three": 3},
+	})
+
+	matched, rest := Partition(maps.All(m), even)
+	assert.DeepEqual(t, matched, map[string]int{"two": 2, "four": 4})
+	assert.DeepEqual(t, rest, map[string]int{"one": 1, "three": 3})
+}
+
+func TestSeqStops(t *testing.T) {
+	m := map[string]string{"a": "x", "b": "y", "c": "z"}
+	seq := MapValues(Filter(maps.All(m), func(string, string) bool { return true }), strings.ToUpper)
+	n := 0
+	for range seq {
+		n++
+		break
+	}
+	assert.Equal(t, n, 1)
+}
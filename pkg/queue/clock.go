@@ -0,0 +1,112 @@
+// Copyright 2026 Outreach Corporation. All Rights Reserved.
+
+// Description: Provides the clocks used by the delay queues.
+
+package queue
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock tells the time and creates timers. It is used by DelayQueue and
+// TimingWheel so that tests can control time with a ManualClock.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// After returns a channel which receives the current time once d
+	// has elapsed.
+	After(d time.Duration) <-chan time.Time
+}
+
+// SystemClock returns the Clock backed by the time package.
+func SystemClock() Clock {
+	return systemClock{}
+}
+
+// systemClock implements the Clock returned by SystemClock
+type systemClock struct{}
+
+// Now implements the Clock interface.
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// After implements the Clock interface.
+func (systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// ManualClock is a Clock whose time only changes when it is set or
+// advanced, which makes tests of time based code deterministic.
+type ManualClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []manualWaiter
+}
+
+// manualWaiter is a channel returned by ManualClock.After
+type manualWaiter struct {
+	at time.Time
+	ch chan time.Time
+}
+
+// NewManualClock creates a ManualClock set to now.
+func NewManualClock(now time.Time) *ManualClock {
+	return &ManualClock{now: now}
+}
+
+// Now implements the Clock interface.
+func (c *ManualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// After implements the Clock interface. The channel receives the time
+// once the clock is advanced by at least d.
+func (c *ManualClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- c.now
+		return ch
+	}
+	c.waiters = append(c.waiters, manualWaiter{at: c.now.Add(d), ch: ch})
+	return ch
+}
+
+// Advance moves the clock forward by d, firing the timers which are due.
+func (c *ManualClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	now := c.now.Add(d)
+	c.mu.Unlock()
+	c.Set(now)
+}
+
+// Set sets the time of the clock, firing the timers which are due.
+func (c *ManualClock) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = now
+
+	waiters := c.waiters[:0]
+	for _, w := range c.waiters {
+		if w.at.After(now) {
+			waiters = append(waiters, w)
+			continue
+		}
+		w.ch <- now
+	}
+	c.waiters = waiters
+}
+
+// Waiters returns the number of pending timers. Tests can use it to wait
+// until the code under test is blocked on the clock before advancing it.
+func (c *ManualClock) Waiters() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.waiters)
+}
@@ -0,0 +1,166 @@
+// Copyright 2026 Outreach Corporation. All Rights Reserved.
+
+// Description: Provides a delay queue.
+
+package queue
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DelayOption is used to change the configuration of a DelayQueue or a
+// TimingWheel.
+type DelayOption func(c *delayConfig)
+
+// delayConfig is the configuration of the delay queues.
+type delayConfig struct {
+	clock Clock
+}
+
+// newDelayConfig applies the options to the default configuration.
+func newDelayConfig(opts []DelayOption) delayConfig {
+	c := delayConfig{clock: SystemClock()}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+// WithClock sets the clock used to tell when items are due. Defaults
+// to SystemClock.
+func WithClock(clock Clock) DelayOption {
+	return func(c *delayConfig) {
+		c.clock = clock
+	}
+}
+
+// DelayQueue holds items until their due time, for example retries
+// with a retry-after, expiring entries or deferred webhooks. Consumers
+// only receive items once they are due, the earliest first.
+//
+// It is backed by a min-heap of due times, so adding and removing items
+// costs O(log n); see TimingWheel for very large numbers of timers.
+// DelayQueue is safe for concurrent use.
+type DelayQueue[T any] struct {
+	mu    sync.Mutex
+	q     *PriorityQueueOf[T]
+	clock Clock
+
+	// changed is closed and replaced whenever the earliest due time may
+	// have changed, to wake up the consumers blocked in PopWait
+	changed chan struct{}
+}
+
+// NewDelayQueue creates a new delay queue.
+func NewDelayQueue[T any](opts ...DelayOption) *DelayQueue[T] {
+	c := newDelayConfig(opts)
+	return &DelayQueue[T]{
+		q:       NewPriorityQueueOf[T](),
+		clock:   c.clock,
+		changed: make(chan struct{}),
+	}
+}
+
+// notify wakes up the consumers blocked in PopWait, q.mu must be held.
+func (q *DelayQueue[T]) notify() {
+	close(q.changed)
+	q.changed = make(chan struct{})
+}
+
+// Push adds an item due at the given time. The returned handle can be
+// used to reschedule or remove the item.
+func (q *DelayQueue[T]) Push(data T, at time.Time) *ItemOf[T] {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	//nolint:errcheck // Why: the queue has no capacity limit
+	item, _ := q.q.Push(data, at.UnixNano())
+	q.notify()
+	return item
+}
+
+// PushAfter adds an item due after the given delay.
+func (q *DelayQueue[T]) PushAfter(data T, d time.Duration) *ItemOf[T] {
+	return q.Push(data, q.clock.Now().Add(d))
+}
+
+// Reschedule changes the due time of an item. Return error if the item is
+// not in the queue.
+func (q *DelayQueue[T]) Reschedule(item *ItemOf[T], at time.Time) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if !q.q.Contains(item) {
+		return errors.New("item is not in queue")
+	}
+	item.SetPriority(at.UnixNano())
+	q.notify()
+	return q.q.Update(item)
+}
+
+// Remove an item from the queue before it is due and return it. If the
+// item does not exist in queue, return nil.
+func (q *DelayQueue[T]) Remove(item *ItemOf[T]) *ItemOf[T] {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.q.Remove(item)
+}
+
+// Len returns the number of items in the queue, due or not.
+func (q *DelayQueue[T]) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.q.Len()
+}
+
+// Pop removes and returns the earliest item if it is due. It returns
+// false if no item is due.
+func (q *DelayQueue[T]) Pop() (T, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	data, _, ok := q.pop()
+	return data, ok
+}
+
+// PopWait removes and returns the earliest item, blocking until it is
+// due. It returns the context error if the context is done first.
+func (q *DelayQueue[T]) PopWait(ctx context.Context) (T, error) {
+	for {
+		q.mu.Lock()
+		data, wait, ok := q.pop()
+		changed := q.changed
+		q.mu.Unlock()
+		if ok {
+			return data, nil
+		}
+
+		var due <-chan time.Time
+		if wait != math.MaxInt64 {
+			due = q.clock.After(wait)
+		}
+		select {
+		case <-due:
+		case <-changed:
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		}
+	}
+}
+
+// pop removes the earliest item if it is due, otherwise it returns how
+// long to wait for it (math.MaxInt64 if the queue is empty). q.mu must
+// be held.
+func (q *DelayQueue[T]) pop() (data T, wait time.Duration, ok bool) {
+	item := q.q.Peek()
+	if item == nil {
+		return data, math.MaxInt64, false
+	}
+	if wait := time.Unix(0, item.Priority()).Sub(q.clock.Now()); wait > 0 {
+		return data, wait, false
+	}
+	return q.q.Pop().Data(), 0, true
+}
@@ -0,0 +1,136 @@
+package queue
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+// waitForWaiters blocks until n timers are pending on the clock
+func waitForWaiters(t *testing.T, clock *ManualClock, n int) {
+	t.Helper()
+	for clock.Waiters() < n {
+		select {
+		case <-t.Context().Done():
+			t.Fatal("timed out waiting for timers")
+		case <-time.After(time.Millisecond):
+		}
+	}
+}
+
+func TestDelayQueue_Pop(t *testing.T) {
+	clock := NewManualClock(time.Unix(1000, 0))
+	queue := NewDelayQueue[string](WithClock(clock))
+
+	queue.PushAfter("b", 2*time.Second)
+	queue.PushAfter("a", time.Second)
+	queue.PushAfter("c", 3*time.Second)
+
+	_, ok := queue.Pop()
+	assert.Assert(t, !ok, "nothing is due yet")
+
+	clock.Advance(2 * time.Second)
+	v, ok := queue.Pop()
+	assert.Assert(t, ok)
+	assert.Equal(t, v, "a")
+	v, ok = queue.Pop()
+	assert.Assert(t, ok)
+	assert.Equal(t, v, "b")
+	_, ok = queue.Pop()
+	assert.Assert(t, !ok)
+	assert.Equal(t, queue.Len(), 1)
+}
+
+func TestDelayQueue_RescheduleRemove(t *testing.T) {
+	clock := NewManualClock(time.Unix(1000, 0))
+	queue := NewDelayQueue[string](WithClock(clock))
+
+	a := queue.PushAfter("a", time.Second)
+	b := queue.PushAfter("b", 2*time.Second)
+	assert.NilError(t, queue.Reschedule(b, clock.Now()))
+	assert.Equal(t, queue.Remove(a), a)
+	assert.ErrorContains(t, queue.Reschedule(a, clock.Now()), "not in queue")
+
+	v, ok := queue.Pop()
+	assert.Assert(t, ok)
+	assert.Equal(t, v, "b")
+	assert.Equal(t, queue.Len(), 0)
+}
+
+func TestDelayQueue_PopWait(t *testing.T) {
+	clock := NewManualClock(time.Unix(1000, 0))
+	queue := NewDelayQueue[string](WithClock(clock))
+	queue.PushAfter("later", time.Minute)
+
+	got := make(chan string)
+	go func() {
+		v, err := queue.PopWait(t.Context())
+		assert.Check(t, err)
+		got <- v
+	}()
+
+	// a consumer waiting for a later item picks up an earlier one
+	waitForWaiters(t, clock, 1)
+	queue.PushAfter("sooner", time.Second)
+	waitForWaiters(t, clock, 2)
+	clock.Advance(time.Second)
+	assert.Equal(t, <-got, "sooner")
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	_, err := queue.PopWait(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestTimingWheel(t *testing.T) {
+	clock := NewManualClock(time.Unix(1000, 0))
+	wheel := NewTimingWheel[int](time.Millisecond, 8, WithClock(clock))
+
+	// spread timers across several levels
+	delays := []int{1, 5, 7, 9, 30, 64, 100, 513, 4000}
+	for _, d := range delays {
+		wheel.AddAfter(d, time.Duration(d)*time.Millisecond)
+	}
+	stopped := wheel.AddAfter(-1, 50*time.Millisecond)
+	assert.Assert(t, stopped.Stop())
+	assert.Assert(t, !stopped.Stop())
+	assert.Equal(t, wheel.Len(), len(delays))
+
+	var got []int
+	for elapsed := 0; elapsed <= 4000; elapsed++ {
+		for {
+			v, ok := wheel.Pop()
+			if !ok {
+				break
+			}
+			assert.Equal(t, v, elapsed, "popped at the wrong time")
+			got = append(got, v)
+		}
+		clock.Advance(time.Millisecond)
+	}
+	assert.DeepEqual(t, got, delays)
+	assert.Equal(t, wheel.Len(), 0)
+}
+
+func TestTimingWheel_PopWait(t *testing.T) {
+	clock := NewManualClock(time.Unix(1000, 0))
+	wheel := NewTimingWheel[string](10*time.Millisecond, 16, WithClock(clock))
+	wheel.AddAfter("due", time.Second)
+	wheel.Add("past", clock.Now().Add(-time.Second))
+
+	v, err := wheel.PopWait(t.Context())
+	assert.NilError(t, err)
+	assert.Equal(t, v, "past")
+
+	got := make(chan string)
+	go func() {
+		v, err := wheel.PopWait(t.Context())
+		assert.Check(t, err)
+		got <- v
+	}()
+	waitForWaiters(t, clock, 1)
+	clock.Advance(time.Second)
+	assert.Equal(t, <-got, "due")
+}
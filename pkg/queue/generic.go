@@ -0,0 +1,472 @@
+// Copyright 2026 Outreach Corporation. All Rights Reserved.
+
+// Description: Provides generic priority queues.
+
+package queue
+
+import (
+	"container/heap"
+	"context"
+	"iter"
+	"math"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+)
+
+// ItemOf represents an item in a PriorityQueueOf or SyncPriorityQueueOf. It is
+// the handle used to update the priority of the item or to remove it.
+type ItemOf[T any] struct {
+	data     T
+	priority atomic.Int64
+
+	// seq is the insertion order of the item, used to keep the order of
+	// items with equal priorities stable
+	seq   uint64
+	index int
+}
+
+// Data returns the data of the item.
+func (i *ItemOf[T]) Data() T {
+	return i.data
+}
+
+// Priority returns the priority of the item.
+func (i *ItemOf[T]) Priority() int64 {
+	return i.priority.Load()
+}
+
+// SetPriority sets the priority of the item. Call Update on the queue
+// holding the item for the change to take effect.
+func (i *ItemOf[T]) SetPriority(v int64) {
+	i.priority.Store(v)
+}
+
+// itemsOf implements the heap interface for items of a PriorityQueueOf.
+type itemsOf[T any] struct {
+	isMinHeap bool
+	items     []*ItemOf[T]
+}
+
+// _ ensures itemsOf implements the heap interface.
+var _ heap.Interface = (*itemsOf[int])(nil)
+
+// Len returns the queue size.
+func (q *itemsOf[T]) Len() int {
+	return len(q.items)
+}
+
+// Less compares items at index i and j. Items with equal priorities
+// are ordered by insertion.
+func (q *itemsOf[T]) Less(i, j int) bool {
+	return q.before(q.items[i], q.items[j])
+}
+
+// before reports whether a comes out of the queue before b.
+func (q *itemsOf[T]) before(a, b *ItemOf[T]) bool {
+	pa, pb := a.Priority(), b.Priority()
+	if pa == pb {
+		return a.seq < b.seq
+	}
+	return q.higher(pa, pb)
+}
+
+// higher reports whether priority a is higher than priority b.
+func (q *itemsOf[T]) higher(a, b int64) bool {
+	if q.isMinHeap {
+		return a < b
+	}
+	return a > b
+}
+
+// lowest returns the item which comes out of the queue last. The queue
+// must not be empty.
+func (q *itemsOf[T]) lowest() *ItemOf[T] {
+	lowest := q.items[0]
+	for _, item := range q.items[1:] {
+		if q.before(lowest, item) {
+			lowest = item
+		}
+	}
+	return lowest
+}
+
+// oldest returns the item which was pushed first. The queue must not be
+// empty.
+func (q *itemsOf[T]) oldest() *ItemOf[T] {
+	oldest := q.items[0]
+	for _, item := range q.items[1:] {
+		if item.seq < oldest.seq {
+			oldest = item
+		}
+	}
+	return oldest
+}
+
+// Swap items at index i and j.
+func (q *itemsOf[T]) Swap(i, j int) {
+	q.items[i], q.items[j] = q.items[j], q.items[i]
+	q.items[i].index = i
+	q.items[j].index = j
+}
+
+// Push an item into queue.
+func (q *itemsOf[T]) Push(x any) {
+	item := x.(*ItemOf[T])
+	item.index = len(q.items)
+	q.items = append(q.items, item)
+}
+
+// Pop removes and returns the last item in the queue.
+func (q *itemsOf[T]) Pop() any {
+	n := len(q.items)
+	item := q.items[n-1]
+	item.index = -1
+	q.items[n-1] = nil
+	q.items = q.items[:n-1]
+	return item
+}
+
+// PriorityQueueOf is a generic priority queue of T, implemented with a
+// heap. It is the generic counterpart of PriorityQueue and accepts the
+// same options; unlike PriorityQueue, items with equal priorities come
+// out in the order they were pushed.
+//
+// PriorityQueueOf is not safe for concurrent use, see SyncPriorityQueueOf.
+type PriorityQueueOf[T any] struct {
+	capacity uint
+	overflow OverflowPolicy
+	onDrop   func(data any, priority int64)
+	items    itemsOf[T]
+	seq      uint64
+}
+
+// NewPriorityQueueOf creates a new generic priority queue.
+func NewPriorityQueueOf[T any](opts ...PriorityQueueOption) *PriorityQueueOf[T] {
+	// the options configure a PriorityQueue, whose settings are copied
+	c := NewPriorityQueue(opts...)
+	return &PriorityQueueOf[T]{
+		capacity: c.capacity,
+		overflow: c.overflow,
+		onDrop:   c.onDrop,
+		items:    itemsOf[T]{isMinHeap: c.queue.isMinHeap},
+	}
+}
+
+// Push an item into the queue. Push returns the item handle that can be used to
+// update its priority or remove it from the queue.
+//
+// When the queue is full, Push applies the overflow policy: it returns ErrQueueFull or
+// drops an item. Unlike PriorityQueue.Push, it does not block with OverflowBlock, which
+// behaves as OverflowReject; use SyncPriorityQueueOf to wait for room.
+func (q *PriorityQueueOf[T]) Push(data T, priority int64) (*ItemOf[T], error) {
+	if q.full() {
+		if err := q.makeRoom(priority); err != nil {
+			return nil, err
+		}
+	}
+	item := &ItemOf[T]{data: data, seq: q.seq}
+	item.priority.Store(priority)
+	q.seq++
+	heap.Push(&q.items, item)
+	return item, nil
+}
+
+// full reports whether the queue is at capacity.
+func (q *PriorityQueueOf[T]) full() bool {
+	// nolint: gosec // Why: The length of a queue should never be negative.
+	return uint(q.items.Len()) >= q.capacity
+}
+
+// makeRoom drops an item from a full queue according to the overflow policy,
+// or returns ErrQueueFull.
+func (q *PriorityQueueOf[T]) makeRoom(priority int64) error {
+	if q.items.Len() == 0 {
+		// the capacity is zero, there will never be room
+		return ErrQueueFull
+	}
+
+	var drop *ItemOf[T]
+	switch q.overflow {
+	case OverflowDropLowest:
+		// the pushed item would be the last of its priority
+		pushed := &ItemOf[T]{seq: math.MaxUint64}
+		pushed.priority.Store(priority)
+		drop = q.items.lowest()
+		if q.items.before(drop, pushed) {
+			return ErrQueueFull
+		}
+	case OverflowDropOldest:
+		drop = q.items.oldest()
+	default:
+		return ErrQueueFull
+	}
+
+	heap.Remove(&q.items, drop.index)
+	if q.onDrop != nil {
+		q.onDrop(drop.data, drop.Priority())
+	}
+	return nil
+}
+
+// Pop removes and returns the first item in the queue. Pop returns nil if the queue is empty.
+func (q *PriorityQueueOf[T]) Pop() *ItemOf[T] {
+	if q.items.Len() == 0 {
+		return nil
+	}
+	return heap.Pop(&q.items).(*ItemOf[T])
+}
+
+// Peek returns the first item in the queue. Peek returns nil if the queue is empty.
+func (q *PriorityQueueOf[T]) Peek() *ItemOf[T] {
+	if q.items.Len() == 0 {
+		return nil
+	}
+	return q.items.items[0]
+}
+
+// Update the item priority in the queue. Call this function when the item priority is
+// changed. Return error if the item is not in the queue.
+func (q *PriorityQueueOf[T]) Update(item *ItemOf[T]) error {
+	if !q.Contains(item) {
+		return errors.New("item is not in queue")
+	}
+	heap.Fix(&q.items, item.index)
+	return nil
+}
+
+// Remove an item from the queue and return the removed item. If the item does not exist in
+// queue, return nil.
+func (q *PriorityQueueOf[T]) Remove(item *ItemOf[T]) *ItemOf[T] {
+	if !q.Contains(item) {
+		return nil
+	}
+	return heap.Remove(&q.items, item.index).(*ItemOf[T])
+}
+
+// Contains returns true if the item is in the queue.
+func (q *PriorityQueueOf[T]) Contains(item *ItemOf[T]) bool {
+	if item == nil || item.index < 0 || item.index >= q.items.Len() {
+		return false
+	}
+	// Compare the memory address to ensure they are the same item.
+	return q.items.items[item.index] == item
+}
+
+// Clear removes all items in the queue.
+func (q *PriorityQueueOf[T]) Clear() {
+	for _, item := range q.items.items {
+		item.index = -1
+	}
+	q.items.items = nil
+}
+
+// Len returns the number of items in the queue.
+func (q *PriorityQueueOf[T]) Len() int {
+	return q.items.Len()
+}
+
+// List returns a list of items, sorted by priority.
+func (q *PriorityQueueOf[T]) List() []*ItemOf[T] {
+	items := slices.Clone(q.items.items)
+	slices.SortFunc(items, func(a, b *ItemOf[T]) int {
+		if q.items.before(a, b) {
+			return -1
+		}
+		return 1
+	})
+	return items
+}
+
+// All returns an iterator over the data of the items in priority order,
+// without removing them.
+func (q *PriorityQueueOf[T]) All() iter.Seq[T] {
+	return allOf(q.List())
+}
+
+// allOf yields the data of the items
+func allOf[T any](items []*ItemOf[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, item := range items {
+			if !yield(item.data) {
+				return
+			}
+		}
+	}
+}
+
+// SyncPriorityQueueOf is a PriorityQueueOf which is safe for concurrent
+// use. Consumers can block until an item is pushed with PopWait.
+type SyncPriorityQueueOf[T any] struct {
+	mu sync.Mutex
+	q  *PriorityQueueOf[T]
+
+	// pushed is closed and replaced whenever an item is pushed, to
+	// wake up the consumers blocked in PopWait
+	pushed chan struct{}
+
+	// room is closed when an item leaves the queue, to wake up the
+	// producers blocked in Push, it is nil when no producer waits
+	room chan struct{}
+}
+
+// NewSyncPriorityQueueOf creates a new concurrency-safe generic priority queue.
+func NewSyncPriorityQueueOf[T any](opts ...PriorityQueueOption) *SyncPriorityQueueOf[T] {
+	return &SyncPriorityQueueOf[T]{
+		q:      NewPriorityQueueOf[T](opts...),
+		pushed: make(chan struct{}),
+	}
+}
+
+// notify wakes up the consumers blocked in PopWait, q.mu must be held.
+func (q *SyncPriorityQueueOf[T]) notify() {
+	close(q.pushed)
+	q.pushed = make(chan struct{})
+}
+
+// signalRoom wakes up the producers blocked in Push, q.mu must be held.
+func (q *SyncPriorityQueueOf[T]) signalRoom() {
+	if q.room != nil {
+		close(q.room)
+		q.room = nil
+	}
+}
+
+// Push an item into the queue, see PriorityQueueOf.Push. With the OverflowBlock
+// policy, Push blocks until there is room in the queue.
+func (q *SyncPriorityQueueOf[T]) Push(data T, priority int64) (*ItemOf[T], error) {
+	return q.PushWait(context.Background(), data, priority)
+}
+
+// PushWait pushes an item into the queue like Push, but when it blocks because of
+// the OverflowBlock policy, it returns the context error if the context is done
+// first.
+func (q *SyncPriorityQueueOf[T]) PushWait(ctx context.Context, data T, priority int64) (*ItemOf[T], error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for q.q.overflow == OverflowBlock && q.q.full() && q.q.Len() > 0 {
+		if q.room == nil {
+			q.room = make(chan struct{})
+		}
+		room := q.room
+
+		q.mu.Unlock()
+		select {
+		case <-room:
+		case <-ctx.Done():
+			q.mu.Lock()
+			return nil, ctx.Err()
+		}
+		q.mu.Lock()
+	}
+
+	item, err := q.q.Push(data, priority)
+	if err == nil {
+		q.notify()
+	}
+	return item, err
+}
+
+// Pop removes and returns the first item in the queue. Pop returns nil if the queue is empty.
+func (q *SyncPriorityQueueOf[T]) Pop() *ItemOf[T] {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	item := q.q.Pop()
+	if item != nil {
+		q.signalRoom()
+	}
+	return item
+}
+
+// PopWait removes and returns the first item in the queue, blocking
+// until an item is pushed if the queue is empty. It returns the context
+// error if the context is done first.
+func (q *SyncPriorityQueueOf[T]) PopWait(ctx context.Context) (*ItemOf[T], error) {
+	for {
+		q.mu.Lock()
+		item := q.q.Pop()
+		if item != nil {
+			q.signalRoom()
+		}
+		pushed := q.pushed
+		q.mu.Unlock()
+		if item != nil {
+			return item, nil
+		}
+
+		select {
+		case <-pushed:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// Peek returns the first item in the queue. Peek returns nil if the queue is empty.
+func (q *SyncPriorityQueueOf[T]) Peek() *ItemOf[T] {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.q.Peek()
+}
+
+// Update the item priority in the queue, see PriorityQueueOf.Update.
+func (q *SyncPriorityQueueOf[T]) Update(item *ItemOf[T]) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.q.Update(item)
+}
+
+// Remove an item from the queue, see PriorityQueueOf.Remove.
+func (q *SyncPriorityQueueOf[T]) Remove(item *ItemOf[T]) *ItemOf[T] {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	removed := q.q.Remove(item)
+	if removed != nil {
+		q.signalRoom()
+	}
+	return removed
+}
+
+// Contains returns true if the item is in the queue.
+func (q *SyncPriorityQueueOf[T]) Contains(item *ItemOf[T]) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.q.Contains(item)
+}
+
+// Clear removes all items in the queue.
+func (q *SyncPriorityQueueOf[T]) Clear() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.q.Clear()
+	q.signalRoom()
+}
+
+// Len returns the number of items in the queue.
+func (q *SyncPriorityQueueOf[T]) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.q.Len()
+}
+
+// List returns a list of items, sorted by priority.
+func (q *SyncPriorityQueueOf[T]) List() []*ItemOf[T] {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.q.List()
+}
+
+// All returns an iterator over the data of the items in priority order,
+// without removing them. It iterates over a snapshot of the queue taken
+// when iteration starts.
+func (q *SyncPriorityQueueOf[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for data := range allOf(q.List()) {
+			if !yield(data) {
+				return
+			}
+		}
+	}
+}
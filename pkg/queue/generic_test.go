@@ -0,0 +1,121 @@
+package queue
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestPriorityQueueOf_Stable(t *testing.T) {
+	queue := NewPriorityQueueOf[string]()
+	for _, v := range []string{"b1", "a1", "b2", "c1", "a2", "b3"} {
+		_, err := queue.Push(v, int64(v[0]))
+		assert.NilError(t, err)
+	}
+
+	assert.DeepEqual(t, slices.Collect(queue.All()), []string{"a1", "a2", "b1", "b2", "b3", "c1"})
+	assert.Equal(t, queue.Len(), 6, "All does not consume the queue")
+
+	var popped []string
+	for item := queue.Pop(); item != nil; item = queue.Pop() {
+		popped = append(popped, item.Data())
+	}
+	assert.DeepEqual(t, popped, []string{"a1", "a2", "b1", "b2", "b3", "c1"})
+}
+
+func TestPriorityQueueOf_MaxHeap(t *testing.T) {
+	queue := NewPriorityQueueOf[int](WithMaxHeap(), WithCapacity(3))
+	for i := range 3 {
+		_, err := queue.Push(i, int64(i))
+		assert.NilError(t, err)
+	}
+	_, err := queue.Push(3, 3)
+	assert.ErrorContains(t, err, "queue is full")
+	assert.DeepEqual(t, slices.Collect(queue.All()), []int{2, 1, 0})
+}
+
+func TestPriorityQueueOf_UpdateRemove(t *testing.T) {
+	queue := NewPriorityQueueOf[int]()
+	items := make([]*ItemOf[int], 5)
+	for i := range items {
+		items[i], _ = queue.Push(i, int64(i))
+	}
+
+	items[4].SetPriority(-1)
+	assert.NilError(t, queue.Update(items[4]))
+	assert.Equal(t, queue.Peek(), items[4])
+
+	assert.Equal(t, queue.Remove(items[2]), items[2])
+	assert.Assert(t, !queue.Contains(items[2]))
+	assert.Assert(t, queue.Remove(items[2]) == nil)
+	assert.ErrorContains(t, queue.Update(items[2]), "not in queue")
+	assert.DeepEqual(t, slices.Collect(queue.All()), []int{4, 0, 1, 3})
+
+	queue.Clear()
+	assert.Equal(t, queue.Len(), 0)
+	assert.Assert(t, !queue.Contains(items[0]))
+}
+
+func TestSyncPriorityQueueOf_PopWait(t *testing.T) {
+	queue := NewSyncPriorityQueueOf[int]()
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		//nolint:errcheck // Why: the queue has no capacity
+		queue.Push(42, 0)
+	}()
+	item, err := queue.PopWait(t.Context())
+	assert.NilError(t, err)
+	assert.Equal(t, item.Data(), 42)
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+	defer cancel()
+	_, err = queue.PopWait(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestSyncPriorityQueueOf_Concurrent(t *testing.T) {
+	queue := NewSyncPriorityQueueOf[int]()
+	n := 100
+
+	var (
+		wg  sync.WaitGroup
+		mu  sync.Mutex
+		got []int
+	)
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				mu.Lock()
+				done := len(got) == n
+				mu.Unlock()
+				if done {
+					return
+				}
+				ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+				item, err := queue.PopWait(ctx)
+				cancel()
+				if err == nil {
+					mu.Lock()
+					got = append(got, item.Data())
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	for i := range n {
+		_, err := queue.Push(i, int64(i))
+		assert.NilError(t, err)
+	}
+	wg.Wait()
+
+	slices.Sort(got)
+	assert.Equal(t, len(got), n)
+	assert.Equal(t, got[n-1], n-1)
+}
@@ -0,0 +1,75 @@
+// Copyright 2026 Outreach Corporation. All Rights Reserved.
+
+// Description: Provides the overflow policies of bounded priority queues.
+
+package queue
+
+import (
+	"github.com/pkg/errors"
+)
+
+// ErrQueueFull is returned by Push when the queue is at capacity and the
+// overflow policy does not make room for the item.
+var ErrQueueFull = errors.New("queue is full")
+
+// OverflowPolicy tells a bounded priority queue what to do when an item is
+// pushed while the queue is at capacity.
+type OverflowPolicy int
+
+// This section contains the overflow policies.
+const (
+	// OverflowReject makes Push return ErrQueueFull. This is the default.
+	OverflowReject OverflowPolicy = iota
+
+	// OverflowDropLowest drops the item with the lowest priority to make
+	// room, the most recently pushed one among equal priorities. If the
+	// pushed item has the lowest priority, Push returns ErrQueueFull.
+	OverflowDropLowest
+
+	// OverflowDropOldest drops the item which was pushed first to make
+	// room, regardless of its priority.
+	OverflowDropOldest
+
+	// OverflowBlock makes Push block until an item is popped or removed.
+	// PriorityQueueOf is not safe for concurrent use, so it cannot wait
+	// and returns ErrQueueFull instead.
+	OverflowBlock
+)
+
+// String returns the name of the policy.
+func (p OverflowPolicy) String() string {
+	switch p {
+	case OverflowReject:
+		return "reject"
+	case OverflowDropLowest:
+		return "drop_lowest"
+	case OverflowDropOldest:
+		return "drop_oldest"
+	case OverflowBlock:
+		return "block"
+	default:
+		return "unknown"
+	}
+}
+
+// WithOverflowPolicy sets what Push does when the queue is at the capacity
+// set by WithCapacity. Defaults to OverflowReject.
+//
+// PriorityQueue and SyncPriorityQueueOf apply every policy. PriorityQueueOf is
+// not safe for concurrent use, so nothing could make room while it waits: it
+// applies OverflowBlock as OverflowReject.
+func WithOverflowPolicy(p OverflowPolicy) PriorityQueueOption {
+	return func(q *PriorityQueue) {
+		q.overflow = p
+	}
+}
+
+// WithDropHandler sets a function called with the data and priority of
+// the items dropped by the OverflowDropLowest and OverflowDropOldest
+// policies. It is called with the queue locked and must not use the
+// queue.
+func WithDropHandler(fn func(data any, priority int64)) PriorityQueueOption {
+	return func(q *PriorityQueue) {
+		q.onDrop = fn
+	}
+}
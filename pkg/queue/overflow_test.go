@@ -0,0 +1,121 @@
+package queue
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestPriorityQueue_DropLowest(t *testing.T) {
+	var dropped []any
+	queue := NewPriorityQueue(WithCapacity(3), WithOverflowPolicy(OverflowDropLowest),
+		WithDropHandler(func(data any, _ int64) { dropped = append(dropped, data) }))
+	for _, p := range []int64{1, 5, 5} {
+		_, err := queue.Push(p, p)
+		assert.NilError(t, err)
+	}
+
+	// the most recent item with the lowest priority goes first
+	item, err := queue.Push(int64(2), 2)
+	assert.NilError(t, err)
+	assert.Assert(t, queue.Contains(item))
+	assert.Equal(t, queue.Len(), 3)
+
+	_, err = queue.Push(int64(9), 9)
+	assert.ErrorIs(t, err, ErrQueueFull)
+
+	assert.Equal(t, len(dropped), 1)
+	var got []int64
+	for _, item := range queue.List() {
+		got = append(got, item.GetPriority())
+	}
+	assert.DeepEqual(t, got, []int64{1, 2, 5})
+}
+
+func TestPriorityQueue_DropOldest(t *testing.T) {
+	queue := NewPriorityQueue(WithCapacity(2), WithOverflowPolicy(OverflowDropOldest), WithMaxHeap())
+	first, _ := queue.Push("first", 9)
+	_, _ = queue.Push("second", 1)
+	_, err := queue.Push("third", 0)
+	assert.NilError(t, err)
+	assert.Assert(t, !queue.Contains(first))
+	assert.Equal(t, queue.Pop().GetData(), "second")
+	assert.Equal(t, queue.Pop().GetData(), "third")
+}
+
+func TestPriorityQueue_Block(t *testing.T) {
+	queue := NewPriorityQueue(WithCapacity(1), WithOverflowPolicy(OverflowBlock))
+	_, _ = queue.Push("first", 1)
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+	defer cancel()
+	_, err := queue.PushWait(ctx, "second", 1)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	pushed := make(chan error)
+	go func() {
+		_, err := queue.Push("second", 1)
+		pushed <- err
+	}()
+	select {
+	case <-pushed:
+		t.Fatal("push did not block on a full queue")
+	case <-time.After(10 * time.Millisecond):
+	}
+	assert.Equal(t, queue.Pop().GetData(), "first")
+	assert.NilError(t, <-pushed)
+	assert.Equal(t, queue.Pop().GetData(), "second")
+
+	_, err = NewPriorityQueue(WithCapacity(0), WithOverflowPolicy(OverflowBlock)).Push(1, 1)
+	assert.ErrorIs(t, err, ErrQueueFull, "a queue without capacity never has room")
+}
+
+func TestPriorityQueueOf_Overflow(t *testing.T) {
+	lowest := NewPriorityQueueOf[string](WithCapacity(2), WithOverflowPolicy(OverflowDropLowest))
+	_, _ = lowest.Push("a", 1)
+	_, _ = lowest.Push("b", 2)
+	_, err := lowest.Push("c", 2)
+	assert.ErrorIs(t, err, ErrQueueFull, "equal priorities do not drop older items")
+	_, err = lowest.Push("d", 0)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, slices.Collect(lowest.All()), []string{"d", "a"})
+
+	oldest := NewPriorityQueueOf[string](WithCapacity(2), WithOverflowPolicy(OverflowDropOldest))
+	_, _ = oldest.Push("a", 1)
+	_, _ = oldest.Push("b", 2)
+	_, err = oldest.Push("c", 3)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, slices.Collect(oldest.All()), []string{"b", "c"})
+
+	block := NewPriorityQueueOf[string](WithCapacity(1), WithOverflowPolicy(OverflowBlock))
+	_, _ = block.Push("a", 1)
+	_, err = block.Push("b", 1)
+	assert.ErrorIs(t, err, ErrQueueFull, "PriorityQueueOf cannot block")
+}
+
+func TestSyncPriorityQueueOf_Block(t *testing.T) {
+	queue := NewSyncPriorityQueueOf[int](WithCapacity(2), WithOverflowPolicy(OverflowBlock))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := range 10 {
+			_, err := queue.Push(i, int64(i))
+			assert.Check(t, err)
+		}
+	}()
+
+	var got []int
+	for range 10 {
+		item, err := queue.PopWait(t.Context())
+		assert.NilError(t, err)
+		assert.Assert(t, queue.Len() <= 2)
+		got = append(got, item.Data())
+	}
+	<-done
+	slices.Sort(got)
+	assert.DeepEqual(t, got, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9})
+}
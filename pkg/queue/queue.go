@@ -46,10 +46,60 @@
 //	pq, _ = queue.NewPriorityQueue(queue.WithMaxHeap())
 //	pq.Push(1, 1)	// lower priority
 //	pq.Push(2, 2)	// higher priority
+//
+// # Generic Priority Queue
+//
+// PriorityQueueOf is the generic counterpart of PriorityQueue, it accepts the same
+// options and keeps items with equal priorities in insertion order.
+// SyncPriorityQueueOf is safe for concurrent use and lets consumers block until an
+// item is available.
+//
+//	pq := queue.NewSyncPriorityQueueOf[*Job]()
+//	pq.Push(job, 1)
+//
+//	item, err := pq.PopWait(ctx)	// blocks until an item is pushed
+//
+//	for job := range pq.All() {	// iterates in priority order
+//	}
+//
+// # Bounded Queues and Snapshots
+//
+// By default, Push returns ErrQueueFull when a queue is at the capacity set by
+// WithCapacity. WithOverflowPolicy makes it drop the lowest priority or the oldest
+// item instead, or block until there is room.
+//
+//	pq := queue.NewSyncPriorityQueueOf[*Job](
+//		queue.WithCapacity(1000),
+//		queue.WithOverflowPolicy(queue.OverflowDropLowest),
+//	)
+//
+// The queues can write their contents to an io.Writer and read them back through
+// a Codec, so that pending work survives a restart. PriorityQueue restores its data
+// as the codec decodes an interface, the generic queues restore it with its type.
+//
+//	err := pq.Snapshot(f, queue.JSONCodec)
+//	err = pq.Restore(f, queue.JSONCodec)
+//
+// # Delay Queue and Timing Wheel
+//
+// DelayQueue holds items until their due time and hands them out earliest first.
+// TimingWheel does the same with O(1) adds and stops, for very large numbers of
+// timers, at the cost of rounding due times to a tick. Both take a Clock, so tests
+// can use a ManualClock instead of sleeping.
+//
+//	dq := queue.NewDelayQueue[*Retry]()
+//	dq.PushAfter(retry, retryAfter)
+//
+//	retry, err := dq.PopWait(ctx)	// blocks until the retry is due
+//
+//	tw := queue.NewTimingWheel[string](time.Second, 60)
+//	timer := tw.AddAfter(sessionID, 30*time.Minute)
+//	timer.Stop()
 package queue
 
 import (
 	"container/heap"
+	"context"
 	"math"
 	"sort"
 	"sync"
@@ -103,28 +153,105 @@ func NewPriorityQueue(opts ...PriorityQueueOption) *PriorityQueue {
 
 // PriorityQueue implements a priority queue with a heap. By default, min heap is used with a
 // default queue capacity. Items are sorted by priority, smaller value means higher priority.
-// Queue capacity and heap type can be changed via options.
+// Queue capacity, overflow policy and heap type can be changed via options.
 type PriorityQueue struct {
 	lock     sync.RWMutex
 	capacity uint
+	overflow OverflowPolicy
+	onDrop   func(data any, priority int64)
 	queue    *priorityQueueInternal
+
+	// seq is the insertion order of the next item
+	seq uint64
+
+	// room is closed when an item leaves the queue, to wake up the producers
+	// blocked in Push, it is nil when no producer waits
+	room chan struct{}
 }
 
 // Push an item into the queue. Items in the queue will be sorted by priority. Push returns
 // a PriorityQueueItem that can be used for other operations, such as updating item priority
 // or removing the item from the queue.
+//
+// When the queue is full, Push applies the overflow policy: it returns ErrQueueFull, drops
+// an item or blocks until there is room.
 func (q *PriorityQueue) Push(data interface{}, priority int64) (*PriorityQueueItem, error) {
+	return q.PushWait(context.Background(), data, priority)
+}
+
+// PushWait pushes an item into the queue like Push, but when it blocks because of the
+// OverflowBlock policy, it returns the context error if the context is done first.
+func (q *PriorityQueue) PushWait(ctx context.Context, data interface{}, priority int64) (*PriorityQueueItem, error) {
 	q.lock.Lock()
 	defer q.lock.Unlock()
 	// nolint: gosec // Why: The length of a queue should never be negative.
-	if uint(q.queue.Len()) >= q.capacity {
-		return nil, errors.New("queue is full")
+	for uint(q.queue.Len()) >= q.capacity {
+		if err := q.makeRoom(ctx, priority); err != nil {
+			return nil, err
+		}
 	}
 	item := newPriorityQueueItem(data, priority)
+	item.seq = q.seq
+	q.seq++
 	heap.Push(q.queue, item)
 	return item, nil
 }
 
+// makeRoom applies the overflow policy to a full queue. It returns once an item was dropped
+// or, with OverflowBlock, once an item left the queue. q.lock must be held.
+func (q *PriorityQueue) makeRoom(ctx context.Context, priority int64) error {
+	if q.queue.Len() == 0 {
+		// the capacity is zero, there will never be room
+		return ErrQueueFull
+	}
+
+	var drop *PriorityQueueItem
+	switch q.overflow {
+	case OverflowDropLowest:
+		drop = q.queue.Lowest()
+		if !q.queue.higher(priority, drop.GetPriority()) {
+			return ErrQueueFull
+		}
+	case OverflowDropOldest:
+		drop = q.queue.Oldest()
+	case OverflowBlock:
+		return q.waitForRoom(ctx)
+	default:
+		return ErrQueueFull
+	}
+
+	q.remove(drop)
+	if q.onDrop != nil {
+		q.onDrop(drop.GetData(), drop.GetPriority())
+	}
+	return nil
+}
+
+// waitForRoom releases q.lock until an item leaves the queue or the context is done.
+func (q *PriorityQueue) waitForRoom(ctx context.Context) error {
+	if q.room == nil {
+		q.room = make(chan struct{})
+	}
+	room := q.room
+
+	q.lock.Unlock()
+	defer q.lock.Lock()
+	select {
+	case <-room:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// signalRoom wakes up the producers blocked in Push. q.lock must be held.
+func (q *PriorityQueue) signalRoom() {
+	if q.room != nil {
+		close(q.room)
+		q.room = nil
+	}
+}
+
 // Pop removes and returns the first item in the queue. Pop returns nil if the queue is empty.
 func (q *PriorityQueue) Pop() *PriorityQueueItem {
 	q.lock.Lock()
@@ -133,6 +260,7 @@ func (q *PriorityQueue) Pop() *PriorityQueueItem {
 		return nil
 	}
 	item := heap.Pop(q.queue)
+	q.signalRoom()
 	return item.(*PriorityQueueItem)
 }
 
@@ -166,6 +294,13 @@ func (q *PriorityQueue) Remove(item *PriorityQueueItem) *PriorityQueueItem {
 	if !q.contains(item) {
 		return nil
 	}
+	removedItem := q.remove(item)
+	q.signalRoom()
+	return removedItem
+}
+
+// remove an item which is in the queue. q.lock must be held.
+func (q *PriorityQueue) remove(item *PriorityQueueItem) *PriorityQueueItem {
 	index := item.getIndex()
 	removedItem := q.queue.Remove(index)
 	// Fix the index if removed item is not the last one.
@@ -199,6 +334,7 @@ func (q *PriorityQueue) Clear() {
 	q.lock.Lock()
 	defer q.lock.Unlock()
 	q.queue.Clear()
+	q.signalRoom()
 }
 
 // List returns a list of items, sorted by priority.
@@ -221,6 +357,9 @@ type PriorityQueueItem struct {
 	data     interface{}
 	priority int64
 	index    int
+
+	// seq is the insertion order of the item, used by OverflowDropOldest
+	seq uint64
 }
 
 // newPriorityQueueItem creates a new priority queue item.
@@ -343,6 +482,38 @@ func (q *priorityQueueInternal) List() []*PriorityQueueItem {
 	return items
 }
 
+// Lowest returns the item with the lowest priority, the most recently pushed one among
+// equal priorities. The queue must not be empty.
+func (q *priorityQueueInternal) Lowest() *PriorityQueueItem {
+	lowest := q.items[0]
+	for _, item := range q.items[1:] {
+		p, lp := item.GetPriority(), lowest.GetPriority()
+		if q.higher(lp, p) || (p == lp && item.seq > lowest.seq) {
+			lowest = item
+		}
+	}
+	return lowest
+}
+
+// Oldest returns the item which was pushed first. The queue must not be empty.
+func (q *priorityQueueInternal) Oldest() *PriorityQueueItem {
+	oldest := q.items[0]
+	for _, item := range q.items[1:] {
+		if item.seq < oldest.seq {
+			oldest = item
+		}
+	}
+	return oldest
+}
+
+// higher reports whether priority a is higher than priority b.
+func (q *priorityQueueInternal) higher(a, b int64) bool {
+	if q.isMinHeap {
+		return a < b
+	}
+	return a > b
+}
+
 // Len returns the queue size.
 func (q priorityQueueInternal) Len() int {
 	return len(q.items)
@@ -0,0 +1,200 @@
+// Copyright 2026 Outreach Corporation. All Rights Reserved.
+
+// Description: Provides snapshots of the priority queues.
+
+package queue
+
+import (
+	"encoding/gob"
+	"io"
+	"slices"
+	"sort"
+
+	"github.com/getoutreach/gobox/pkg/codec"
+	"github.com/pkg/errors"
+)
+
+// Encoder encodes values to a stream, such as a json.Encoder or a
+// gob.Encoder.
+type Encoder interface {
+	Encode(v any) error
+}
+
+// Decoder decodes values from a stream, such as a json.Decoder or a
+// gob.Decoder.
+type Decoder interface {
+	Decode(v any) error
+}
+
+// Codec creates the encoders and decoders used to snapshot and restore
+// queues.
+type Codec interface {
+	NewEncoder(w io.Writer) Encoder
+	NewDecoder(r io.Reader) Decoder
+}
+
+// JSONCodec is a Codec which writes snapshots as JSON. Decoding errors
+// include a snippet of the payload, see codec.JSON.
+var JSONCodec Codec = jsonCodec{} // nolint:gochecknoglobals // Why: cleaner to use everywhere
+
+// GobCodec is a Codec which writes snapshots with encoding/gob. Interface
+// types in the data must be registered with gob.Register.
+var GobCodec Codec = gobCodec{} // nolint:gochecknoglobals // Why: cleaner to use everywhere
+
+// jsonCodec implements JSONCodec
+type jsonCodec struct{}
+
+// NewEncoder implements the Codec interface.
+func (jsonCodec) NewEncoder(w io.Writer) Encoder {
+	return (&codec.JSON{}).NewEncoder(w)
+}
+
+// NewDecoder implements the Codec interface.
+func (jsonCodec) NewDecoder(r io.Reader) Decoder {
+	return (&codec.JSON{}).NewDecoder(r)
+}
+
+// gobCodec implements GobCodec
+type gobCodec struct{}
+
+// NewEncoder implements the Codec interface.
+func (gobCodec) NewEncoder(w io.Writer) Encoder {
+	return gob.NewEncoder(w)
+}
+
+// NewDecoder implements the Codec interface.
+func (gobCodec) NewDecoder(r io.Reader) Decoder {
+	return gob.NewDecoder(r)
+}
+
+// snapshotVersion is the version of the snapshot format
+const snapshotVersion = 1
+
+// snapshot is the encoded form of the contents of a queue
+type snapshot[T any] struct {
+	Version int               `json:"version"`
+	Items   []snapshotItem[T] `json:"items"`
+}
+
+// snapshotItem is the encoded form of an item of a queue
+type snapshotItem[T any] struct {
+	Data     T     `json:"data"`
+	Priority int64 `json:"priority"`
+}
+
+// writeSnapshot encodes the items, in priority order, to w
+func writeSnapshot[T any](w io.Writer, c Codec, items []*ItemOf[T]) error {
+	s := snapshot[T]{Version: snapshotVersion, Items: make([]snapshotItem[T], len(items))}
+	for i, item := range items {
+		s.Items[i] = snapshotItem[T]{Data: item.data, Priority: item.Priority()}
+	}
+	return encodeSnapshot(w, c, &s)
+}
+
+// encodeSnapshot encodes s to w
+func encodeSnapshot[T any](w io.Writer, c Codec, s *snapshot[T]) error {
+	return errors.Wrap(c.NewEncoder(w).Encode(s), "failed to encode queue snapshot")
+}
+
+// readSnapshot decodes a snapshot written by writeSnapshot from r
+func readSnapshot[T any](r io.Reader, c Codec) ([]snapshotItem[T], error) {
+	var s snapshot[T]
+	if err := c.NewDecoder(r).Decode(&s); err != nil {
+		return nil, errors.Wrap(err, "failed to decode queue snapshot")
+	}
+	if s.Version != snapshotVersion {
+		return nil, errors.Errorf("unsupported queue snapshot version %d", s.Version)
+	}
+	return s.Items, nil
+}
+
+// Snapshot writes the data and priorities of the items in the queue to w, in
+// priority order, so that they can be restored with Restore, for example after
+// a restart. The queue is not modified.
+func (q *PriorityQueueOf[T]) Snapshot(w io.Writer, c Codec) error {
+	return writeSnapshot(w, c, q.List())
+}
+
+// Restore reads a snapshot written by Snapshot from r and pushes its items into
+// the queue. Items with equal priorities keep their order. The items are pushed
+// as by Push, so the overflow policy applies if the queue becomes full; the first
+// push error is returned.
+func (q *PriorityQueueOf[T]) Restore(r io.Reader, c Codec) error {
+	items, err := readSnapshot[T](r, c)
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		if _, err := q.Push(item.Data, item.Priority); err != nil {
+			return errors.Wrap(err, "failed to restore queue snapshot")
+		}
+	}
+	return nil
+}
+
+// Snapshot writes the contents of the queue to w, see PriorityQueueOf.Snapshot.
+// The queue is only locked while its items are listed.
+func (q *SyncPriorityQueueOf[T]) Snapshot(w io.Writer, c Codec) error {
+	return writeSnapshot(w, c, q.List())
+}
+
+// Restore reads a snapshot from r and pushes its items into the queue, see
+// PriorityQueueOf.Restore. Consumers blocked in PopWait receive the items as they
+// are pushed; with the OverflowBlock policy, Restore blocks until there is room.
+func (q *SyncPriorityQueueOf[T]) Restore(r io.Reader, c Codec) error {
+	items, err := readSnapshot[T](r, c)
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		if _, err := q.Push(item.Data, item.Priority); err != nil {
+			return errors.Wrap(err, "failed to restore queue snapshot")
+		}
+	}
+	return nil
+}
+
+// Snapshot writes the data and priorities of the items in the queue to w, in
+// priority order, see PriorityQueueOf.Snapshot. The queue is only locked while
+// its items are listed.
+//
+// The data is encoded as any: JSONCodec restores it as the types encoding/json
+// decodes into an interface, such as map[string]any or float64, and GobCodec
+// needs its types registered with gob.Register. Use PriorityQueueOf to restore
+// the data with its type.
+func (q *PriorityQueue) Snapshot(w io.Writer, c Codec) error {
+	q.lock.RLock()
+	items := slices.Clone(q.queue.items)
+	q.lock.RUnlock()
+
+	// unlike List, keep the items with equal priorities in insertion order
+	sort.Slice(items, func(i, j int) bool {
+		pi, pj := items[i].GetPriority(), items[j].GetPriority()
+		if pi != pj {
+			return q.queue.higher(pi, pj)
+		}
+		return items[i].seq < items[j].seq
+	})
+
+	s := snapshot[any]{Version: snapshotVersion, Items: make([]snapshotItem[any], len(items))}
+	for i, item := range items {
+		s.Items[i] = snapshotItem[any]{Data: item.GetData(), Priority: item.GetPriority()}
+	}
+	return encodeSnapshot(w, c, &s)
+}
+
+// Restore reads a snapshot written by Snapshot from r and pushes its items into
+// the queue, see PriorityQueueOf.Restore. With the OverflowBlock policy, Restore
+// blocks until there is room.
+func (q *PriorityQueue) Restore(r io.Reader, c Codec) error {
+	items, err := readSnapshot[any](r, c)
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		if _, err := q.Push(item.Data, item.Priority); err != nil {
+			return errors.Wrap(err, "failed to restore queue snapshot")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package queue
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+type job struct {
+	ID   string
+	Args []string
+}
+
+func TestSnapshotRestore(t *testing.T) {
+	for name, c := range map[string]Codec{"json": JSONCodec, "gob": GobCodec} {
+		t.Run(name, func(t *testing.T) {
+			queue := NewPriorityQueueOf[job]()
+			_, _ = queue.Push(job{ID: "b1"}, 2)
+			_, _ = queue.Push(job{ID: "a", Args: []string{"x"}}, 1)
+			_, _ = queue.Push(job{ID: "b2"}, 2)
+
+			var buf bytes.Buffer
+			assert.NilError(t, queue.Snapshot(&buf, c))
+			assert.Equal(t, queue.Len(), 3, "Snapshot does not consume the queue")
+
+			restored := NewSyncPriorityQueueOf[job]()
+			assert.NilError(t, restored.Restore(&buf, c))
+			assert.DeepEqual(t, slices.Collect(restored.All()), slices.Collect(queue.All()))
+		})
+	}
+}
+
+func TestPriorityQueueSnapshotRestore(t *testing.T) {
+	for name, c := range map[string]Codec{"json": JSONCodec, "gob": GobCodec} {
+		t.Run(name, func(t *testing.T) {
+			queue := NewPriorityQueue(WithMaxHeap())
+			_, _ = queue.Push("b1", 1)
+			_, _ = queue.Push("a", 2)
+			_, _ = queue.Push("b2", 1)
+			_, _ = queue.Push("b3", 1)
+
+			var buf bytes.Buffer
+			assert.NilError(t, queue.Snapshot(&buf, c))
+			assert.Equal(t, queue.Len(), 4, "Snapshot does not consume the queue")
+			snap := buf.Bytes()
+
+			// equal priorities are written in insertion order
+			items, err := readSnapshot[any](bytes.NewReader(snap), c)
+			assert.NilError(t, err)
+			assert.DeepEqual(t, items, []snapshotItem[any]{{"a", 2}, {"b1", 1}, {"b2", 1}, {"b3", 1}})
+
+			restored := NewPriorityQueue(WithMaxHeap())
+			assert.NilError(t, restored.Restore(bytes.NewReader(snap), c))
+			var again bytes.Buffer
+			assert.NilError(t, restored.Snapshot(&again, c))
+			assert.DeepEqual(t, again.Bytes(), snap)
+		})
+	}
+
+	queue := NewPriorityQueue(WithCapacity(1))
+	err := queue.Restore(strings.NewReader(`{"version":1,"items":[{"data":1},{"data":2}]}`), JSONCodec)
+	assert.ErrorIs(t, err, ErrQueueFull)
+	assert.Equal(t, queue.Len(), 1)
+}
+
+func TestRestore_Errors(t *testing.T) {
+	queue := NewPriorityQueueOf[int](WithCapacity(1))
+	err := queue.Restore(strings.NewReader(`{"version":1,"items":[{"data":1},{"data":2}]}`), JSONCodec)
+	assert.ErrorIs(t, err, ErrQueueFull)
+	assert.Equal(t, queue.Len(), 1)
+
+	err = queue.Restore(strings.NewReader(`{"version":2}`), JSONCodec)
+	assert.ErrorContains(t, err, "unsupported queue snapshot version 2")
+
+	err = queue.Restore(strings.NewReader(`{"items":`), JSONCodec)
+	assert.ErrorContains(t, err, "failed to decode queue snapshot")
+}
@@ -0,0 +1,294 @@
+// Copyright 2026 Outreach Corporation. All Rights Reserved.
+
+// Description: Provides a hierarchical timing wheel.
+
+package queue
+
+import (
+	"container/list"
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// WheelTimer is the handle of an item added to a TimingWheel.
+type WheelTimer[T any] struct {
+	wheel *TimingWheel[T]
+	data  T
+
+	// due is the due time in nanoseconds
+	due int64
+
+	// bucket and elem locate the timer while it waits in a bucket, they
+	// are nil once the timer is due or stopped
+	bucket  *wheelBucket[T]
+	elem    *list.Element
+	stopped bool
+}
+
+// Data returns the data of the timer.
+func (t *WheelTimer[T]) Data() T {
+	return t.data
+}
+
+// Stop removes the timer from the wheel. It returns false if the timer
+// was already stopped or popped.
+func (t *WheelTimer[T]) Stop() bool {
+	w := t.wheel
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if t.stopped {
+		return false
+	}
+	t.stopped = true
+	if t.bucket != nil {
+		t.bucket.timers.Remove(t.elem)
+		t.bucket, t.elem = nil, nil
+		w.len--
+		return true
+	}
+	for i, ready := range w.ready {
+		if ready == t {
+			w.ready = append(w.ready[:i], w.ready[i+1:]...)
+			w.len--
+			return true
+		}
+	}
+	return false
+}
+
+// wheelBucket holds the timers due within the same tick of a wheel.
+type wheelBucket[T any] struct {
+	timers *list.List
+
+	// expiration is the start of the tick the bucket currently holds, in
+	// nanoseconds, or -1 when the bucket is empty
+	expiration int64
+}
+
+// wheel is a level of a TimingWheel. A level has size buckets of tick
+// nanoseconds, the next level has buckets as large as this level.
+type wheel[T any] struct {
+	tick     int64
+	size     int64
+	interval int64
+
+	// current is the start of the current tick, in nanoseconds
+	current  int64
+	buckets  []*wheelBucket[T]
+	overflow *wheel[T]
+}
+
+// newWheel creates a level of a TimingWheel starting at start
+func newWheel[T any](tick, size, start int64) *wheel[T] {
+	buckets := make([]*wheelBucket[T], size)
+	for i := range buckets {
+		buckets[i] = &wheelBucket[T]{timers: list.New(), expiration: -1}
+	}
+	return &wheel[T]{
+		tick:     tick,
+		size:     size,
+		interval: tick * size,
+		current:  start - start%tick,
+		buckets:  buckets,
+	}
+}
+
+// add places the timer in the bucket of its tick, in this level or the
+// next ones. It returns false if the timer is already due, and the
+// bucket whose expiration changed, if any, which must be scheduled.
+func (w *wheel[T]) add(t *WheelTimer[T]) (added bool, scheduled *wheelBucket[T]) {
+	switch {
+	case t.due < w.current+w.tick:
+		return false, nil
+	case t.due < w.current+w.interval:
+		id := t.due / w.tick
+		b := w.buckets[id%w.size]
+		t.bucket, t.elem = b, b.timers.PushBack(t)
+		if expiration := id * w.tick; b.expiration != expiration {
+			b.expiration = expiration
+			return true, b
+		}
+		return true, nil
+	default:
+		if w.overflow == nil {
+			w.overflow = newWheel[T](w.interval, w.size, w.current)
+		}
+		return w.overflow.add(t)
+	}
+}
+
+// advance moves the current tick of the level and the next ones to the
+// tick including now
+func (w *wheel[T]) advance(now int64) {
+	if now >= w.current+w.tick {
+		w.current = now - now%w.tick
+		if w.overflow != nil {
+			w.overflow.advance(w.current)
+		}
+	}
+}
+
+// TimingWheel is a hierarchical timing wheel: it holds items until
+// their due time, like DelayQueue, but adding and stopping timers costs
+// O(1), which suits very large numbers of timers such as per-request
+// timeouts or TTLs.
+//
+// Due times are rounded down to a tick, so items come out at most one
+// tick early relative to each other but never before the start of their
+// tick. The first level has size buckets of one tick; timers further in
+// the future go to overflow levels, each with buckets as large as the
+// whole previous level, and move down as time passes. Buckets with
+// timers are kept in a min-heap of expirations, so that idle ticks cost
+// nothing.
+//
+// TimingWheel is safe for concurrent use.
+type TimingWheel[T any] struct {
+	mu    sync.Mutex
+	clock Clock
+	root  *wheel[T]
+
+	// buckets holds the buckets with timers, by expiration
+	buckets *PriorityQueueOf[*wheelBucket[T]]
+
+	// ready holds the timers which are due, in order
+	ready []*WheelTimer[T]
+	len   int
+
+	// changed is closed and replaced whenever the earliest expiration may
+	// have changed, to wake up the consumers blocked in PopWait
+	changed chan struct{}
+}
+
+// NewTimingWheel creates a timing wheel with the given tick and number
+// of buckets per level.
+func NewTimingWheel[T any](tick time.Duration, size int, opts ...DelayOption) *TimingWheel[T] {
+	c := newDelayConfig(opts)
+	tick = max(tick, time.Nanosecond)
+	return &TimingWheel[T]{
+		clock:   c.clock,
+		root:    newWheel[T](int64(tick), int64(max(size, 2)), c.clock.Now().UnixNano()),
+		buckets: NewPriorityQueueOf[*wheelBucket[T]](),
+		changed: make(chan struct{}),
+	}
+}
+
+// Add adds an item due at the given time. The returned timer can be
+// stopped to remove the item.
+func (w *TimingWheel[T]) Add(data T, at time.Time) *WheelTimer[T] {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	t := &WheelTimer[T]{wheel: w, data: data, due: at.UnixNano()}
+	if w.buckets.Len() == 0 {
+		// all buckets are empty, catch up with the clock so that the
+		// timer does not go through levels for the time the wheel was idle
+		w.root.advance(w.clock.Now().UnixNano())
+	}
+	w.len++
+	w.add(t)
+	return t
+}
+
+// AddAfter adds an item due after the given delay.
+func (w *TimingWheel[T]) AddAfter(data T, d time.Duration) *WheelTimer[T] {
+	return w.Add(data, w.clock.Now().Add(d))
+}
+
+// add places a timer in the wheel, w.mu must be held.
+func (w *TimingWheel[T]) add(t *WheelTimer[T]) {
+	added, scheduled := w.root.add(t)
+	if !added {
+		t.bucket, t.elem = nil, nil
+		w.ready = append(w.ready, t)
+		w.notify()
+		return
+	}
+	if scheduled != nil {
+		//nolint:errcheck // Why: the queue has no capacity limit
+		w.buckets.Push(scheduled, scheduled.expiration)
+		w.notify()
+	}
+}
+
+// notify wakes up the consumers blocked in PopWait, w.mu must be held.
+func (w *TimingWheel[T]) notify() {
+	close(w.changed)
+	w.changed = make(chan struct{})
+}
+
+// Len returns the number of items in the wheel, due or not.
+func (w *TimingWheel[T]) Len() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.len
+}
+
+// Pop removes and returns the earliest due item. It returns false if no
+// item is due.
+func (w *TimingWheel[T]) Pop() (T, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	data, _, ok := w.pop()
+	return data, ok
+}
+
+// PopWait removes and returns the earliest due item, blocking until an
+// item is due. It returns the context error if the context is done
+// first.
+func (w *TimingWheel[T]) PopWait(ctx context.Context) (T, error) {
+	for {
+		w.mu.Lock()
+		data, wait, ok := w.pop()
+		changed := w.changed
+		w.mu.Unlock()
+		if ok {
+			return data, nil
+		}
+
+		var due <-chan time.Time
+		if wait != math.MaxInt64 {
+			due = w.clock.After(wait)
+		}
+		select {
+		case <-due:
+		case <-changed:
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		}
+	}
+}
+
+// pop flushes the expired buckets and returns the first due timer,
+// otherwise it returns how long to wait for the next bucket to expire
+// (math.MaxInt64 if there is none). w.mu must be held.
+func (w *TimingWheel[T]) pop() (data T, wait time.Duration, ok bool) {
+	now := w.clock.Now().UnixNano()
+	for len(w.ready) == 0 {
+		next := w.buckets.Peek()
+		if next == nil {
+			return data, math.MaxInt64, false
+		}
+		if next.Priority() > now {
+			return data, time.Duration(next.Priority() - now), false
+		}
+
+		// the timers of an expired bucket are either due or move down to
+		// a lower level
+		b := w.buckets.Pop().Data()
+		w.root.advance(b.expiration)
+		timers := b.timers
+		b.timers, b.expiration = list.New(), -1
+		for e := timers.Front(); e != nil; e = e.Next() {
+			w.add(e.Value.(*WheelTimer[T]))
+		}
+	}
+
+	t := w.ready[0]
+	w.ready[0] = nil
+	w.ready = w.ready[1:]
+	w.len--
+	t.stopped = true
+	return t.data, 0, true
+}
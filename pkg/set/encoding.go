@@ -0,0 +1,234 @@
+// Copyright 2026 Outreach Corporation. All Rights Reserved.
+
+// Description: Implements JSON, YAML and text encoding of sets.
+
+package set
+
+import (
+	"cmp"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// DuplicateError is returned when decoding a set from a list which
+// contains the same element more than once.
+type DuplicateError struct {
+	// Value is the duplicated element.
+	Value any
+
+	// Line is the line of the duplicate in a YAML document, or 0.
+	Line int
+}
+
+// Error implements the error interface.
+func (e *DuplicateError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("set contains duplicate element %v at line %d", e.Value, e.Line)
+	}
+	return fmt.Sprintf("set contains duplicate element %v", e.Value)
+}
+
+// MarshalJSON implements json.Marshaler. The set is encoded as an array
+// of its elements, in ascending order (see sortedSlice).
+func (s Set[T]) MarshalJSON() ([]byte, error) {
+	if s == nil {
+		return []byte("null"), nil
+	}
+	return json.Marshal(sortedSlice(s))
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It replaces the contents of
+// the set with the elements of a JSON array, and returns a
+// *DuplicateError if an element is repeated. null leaves the set as is.
+func (s *Set[T]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var elems []T
+	if err := json.Unmarshal(data, &elems); err != nil {
+		return err
+	}
+	return s.fill(elems, nil)
+}
+
+// MarshalYAML implements yaml.Marshaler. The set is encoded as a sequence
+// of its elements, in ascending order (see sortedSlice).
+func (s Set[T]) MarshalYAML() (interface{}, error) {
+	return sortedSlice(s), nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler. It replaces the contents of
+// the set with the elements of a YAML sequence, and returns a
+// *DuplicateError if an element is repeated.
+func (s *Set[T]) UnmarshalYAML(value *yaml.Node) error {
+	var elems []T
+	if err := value.Decode(&elems); err != nil {
+		return err
+	}
+	return s.fill(elems, func(i int) int {
+		if value.Kind == yaml.SequenceNode && i < len(value.Content) {
+			return value.Content[i].Line
+		}
+		return 0
+	})
+}
+
+// MarshalText implements encoding.TextMarshaler. The set is encoded as a
+// comma separated list of its elements, in ascending order (see
+// sortedSlice). Elements are formatted with their MarshalText method if
+// they have one, or fmt.Sprint.
+func (s Set[T]) MarshalText() ([]byte, error) {
+	elems := sortedSlice(s)
+	texts := make([]string, len(elems))
+	for i, v := range elems {
+		text, err := formatElem(v)
+		if err != nil {
+			return nil, err
+		}
+		texts[i] = text
+	}
+	return []byte(strings.Join(texts, ",")), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. It replaces the
+// contents of the set with the elements of a comma separated list, and
+// returns a *DuplicateError if an element is repeated. Elements are
+// parsed with their UnmarshalText method if they have one, otherwise T
+// must be a string, integer, float or boolean type.
+func (s *Set[T]) UnmarshalText(text []byte) error {
+	elems, err := parseList[T](string(text))
+	if err != nil {
+		return err
+	}
+	return s.fill(elems, nil)
+}
+
+// fill replaces the contents of s with elems, which must not contain
+// duplicates. line returns the line of an element for errors, if known.
+func (s *Set[T]) fill(elems []T, line func(i int) int) error {
+	r := make(Set[T], len(elems))
+	for i, v := range elems {
+		if !r.Insert(v) {
+			err := &DuplicateError{Value: v}
+			if line != nil {
+				err.Line = line(i)
+			}
+			return err
+		}
+	}
+	*s = r
+	return nil
+}
+
+// parseList parses a comma separated list of elements. An empty text is
+// an empty list.
+func parseList[T comparable](text string) ([]T, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, nil
+	}
+	parts := strings.Split(text, ",")
+	elems := make([]T, len(parts))
+	for i, part := range parts {
+		v, err := parseElem[T](strings.TrimSpace(part))
+		if err != nil {
+			return nil, err
+		}
+		elems[i] = v
+	}
+	return elems, nil
+}
+
+// parseElem parses an element with its UnmarshalText method or, for
+// basic types, with strconv.
+func parseElem[T comparable](text string) (T, error) {
+	var v T
+	if u, ok := any(&v).(encoding.TextUnmarshaler); ok {
+		err := u.UnmarshalText([]byte(text))
+		return v, err
+	}
+
+	rv := reflect.ValueOf(&v).Elem()
+	switch rv.Kind() { //nolint:exhaustive // Why: the other kinds are not supported
+	case reflect.String:
+		rv.SetString(text)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(text, 0, rv.Type().Bits())
+		if err != nil {
+			return v, err
+		}
+		rv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(text, 0, rv.Type().Bits())
+		if err != nil {
+			return v, err
+		}
+		rv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(text, rv.Type().Bits())
+		if err != nil {
+			return v, err
+		}
+		rv.SetFloat(f)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(text)
+		if err != nil {
+			return v, err
+		}
+		rv.SetBool(b)
+	default:
+		return v, fmt.Errorf("cannot parse set element %q: unsupported type %T", text, v)
+	}
+	return v, nil
+}
+
+// formatElem formats an element with its MarshalText method or
+// fmt.Sprint.
+func formatElem[T comparable](v T) (string, error) {
+	if m, ok := any(v).(encoding.TextMarshaler); ok {
+		text, err := m.MarshalText()
+		return string(text), err
+	}
+	return fmt.Sprint(v), nil
+}
+
+// sortedSlice returns the elements of s in ascending order, so that
+// encodings are deterministic. Strings and numbers are compared by value;
+// other types are sorted by their fmt.Sprint representation, as in
+// Set.String.
+func sortedSlice[T comparable](s Set[T]) []T {
+	elems := make([]T, 0, len(s))
+	for v := range s {
+		elems = append(elems, v)
+	}
+
+	switch reflect.TypeFor[T]().Kind() { //nolint:exhaustive // Why: the other kinds are sorted by fmt.Sprint
+	case reflect.String:
+		slices.SortFunc(elems, func(a, b T) int {
+			return strings.Compare(reflect.ValueOf(a).String(), reflect.ValueOf(b).String())
+		})
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		slices.SortFunc(elems, func(a, b T) int {
+			return cmp.Compare(reflect.ValueOf(a).Int(), reflect.ValueOf(b).Int())
+		})
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		slices.SortFunc(elems, func(a, b T) int {
+			return cmp.Compare(reflect.ValueOf(a).Uint(), reflect.ValueOf(b).Uint())
+		})
+	case reflect.Float32, reflect.Float64:
+		slices.SortFunc(elems, func(a, b T) int {
+			return cmp.Compare(reflect.ValueOf(a).Float(), reflect.ValueOf(b).Float())
+		})
+	default:
+		slices.SortStableFunc(elems, func(a, b T) int {
+			return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+		})
+	}
+	return elems
+}
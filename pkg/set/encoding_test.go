@@ -0,0 +1,82 @@
+package set
+
+import (
+	"encoding/json"
+	"errors"
+	"net/netip"
+	"testing"
+
+	"go.yaml.in/yaml/v3"
+	"gotest.tools/v3/assert"
+)
+
+type config struct {
+	Regions Set[string] `json:"regions" yaml:"regions"`
+	Ports   Set[int]    `json:"ports" yaml:"ports"`
+}
+
+func TestJSON(t *testing.T) {
+	c := config{Regions: Of("us-west-2", "eu-west-1", "us-east-1"), Ports: Of(443, 80, 8080)}
+	data, err := json.Marshal(c)
+	assert.NilError(t, err)
+	assert.Equal(t, string(data), `{"regions":["eu-west-1","us-east-1","us-west-2"],"ports":[80,443,8080]}`)
+
+	var got config
+	assert.NilError(t, json.Unmarshal(data, &got))
+	assert.DeepEqual(t, got, c)
+
+	data, err = json.Marshal(config{})
+	assert.NilError(t, err)
+	assert.Equal(t, string(data), `{"regions":null,"ports":null}`)
+}
+
+func TestJSONDuplicate(t *testing.T) {
+	var c config
+	err := json.Unmarshal([]byte(`{"regions":["a","b","a"]}`), &c)
+	var dupErr *DuplicateError
+	assert.Assert(t, errors.As(err, &dupErr))
+	assert.Equal(t, dupErr.Value, "a")
+	assert.ErrorContains(t, err, "set contains duplicate element a")
+
+	err = json.Unmarshal([]byte(`{"regions":{"a":{}}}`), &c)
+	assert.ErrorContains(t, err, "cannot unmarshal object")
+}
+
+func TestYAML(t *testing.T) {
+	c := config{Regions: Of("b", "a"), Ports: Of(2, 10)}
+	data, err := yaml.Marshal(c)
+	assert.NilError(t, err)
+	assert.Equal(t, string(data), "regions:\n    - a\n    - b\nports:\n    - 2\n    - 10\n")
+
+	// decoding replaces the defaults
+	got := config{Regions: Of("default")}
+	assert.NilError(t, yaml.Unmarshal(data, &got))
+	assert.DeepEqual(t, got, c)
+
+	err = yaml.Unmarshal([]byte("regions:\n  - a\n  - b\n  - a\n"), &got)
+	assert.ErrorContains(t, err, "set contains duplicate element a at line 4")
+}
+
+func TestText(t *testing.T) {
+	text, err := Of(10, 9, 100).MarshalText()
+	assert.NilError(t, err)
+	assert.Equal(t, string(text), "9,10,100")
+
+	var ints Set[int]
+	assert.NilError(t, ints.UnmarshalText([]byte(" 3, 1,2")))
+	assert.DeepEqual(t, ints, Of(1, 2, 3))
+	assert.ErrorContains(t, ints.UnmarshalText([]byte("1,x")), "invalid syntax")
+	assert.ErrorContains(t, ints.UnmarshalText([]byte("1,1")), "duplicate element 1")
+
+	assert.NilError(t, ints.UnmarshalText(nil))
+	assert.Equal(t, ints.Len(), 0)
+
+	// elements implementing encoding.TextUnmarshaler
+	var addrs Set[netip.Addr]
+	assert.NilError(t, addrs.UnmarshalText([]byte("10.0.0.2,10.0.0.1")))
+	assert.Assert(t, addrs.Contains(netip.MustParseAddr("10.0.0.1")))
+
+	type point struct{ X, Y int }
+	var points Set[point]
+	assert.ErrorContains(t, points.UnmarshalText([]byte("1")), "unsupported type set.point")
+}
@@ -5,6 +5,7 @@ package set_test
 
 import (
 	"fmt"
+	"slices"
 
 	"github.com/getoutreach/gobox/pkg/set"
 )
@@ -43,3 +44,25 @@ func Example_iterate() {
 	}
 	// Output: x
 }
+
+func Example_ordered() {
+	s := set.OrderedOf(30, 10, 20, 40)
+	fmt.Println(s.Slice())
+	fmt.Println(slices.Collect(s.Range(15, 35)))
+	lowest, _ := s.Min()
+	fmt.Println(lowest)
+	// Output:
+	// [10 20 30 40]
+	// [20 30]
+	// 10
+}
+
+func Example_immutable() {
+	a := set.ImmutableOf("x", "y")
+	b := a.Insert("z")
+	fmt.Println(a)
+	fmt.Println(b)
+	// Output:
+	// {x, y}
+	// {x, y, z}
+}
@@ -0,0 +1,74 @@
+// Copyright 2026 Outreach Corporation. All Rights Reserved.
+
+// Description: Implements command line flags holding sets.
+
+package set
+
+import (
+	"flag"
+)
+
+// FlagValue is a flag.Value which parses a comma separated list into a
+// set, for example --regions a,b,c. The flag can be repeated to add more
+// elements; the first occurrence replaces the default value of the set.
+// A repeated element is an error.
+//
+// Elements are parsed as by Set.UnmarshalText.
+//
+// With the standard flag package:
+//
+//	regions := set.Of("us-east-1")
+//	flag.Var(set.Flag(&regions), "regions", "regions to deploy to")
+//
+// With urfave/cli, v2 or v3, FlagValue is a cli.Generic:
+//
+//	&cli.GenericFlag{Name: "regions", Value: set.Flag(&regions)}
+type FlagValue[T comparable] struct {
+	s   *Set[T]
+	set bool
+}
+
+// _ ensures FlagValue implements the flag.Getter interface.
+var _ flag.Getter = (*FlagValue[string])(nil)
+
+// Flag returns a FlagValue storing the parsed elements in s.
+func Flag[T comparable](s *Set[T]) *FlagValue[T] {
+	return &FlagValue[T]{s: s}
+}
+
+// Set implements flag.Value.
+func (f *FlagValue[T]) Set(value string) error {
+	elems, err := parseList[T](value)
+	if err != nil {
+		return err
+	}
+	if !f.set || *f.s == nil {
+		*f.s = make(Set[T], len(elems))
+		f.set = true
+	}
+	for _, v := range elems {
+		if !f.s.Insert(v) {
+			return &DuplicateError{Value: v}
+		}
+	}
+	return nil
+}
+
+// String implements flag.Value. It returns the elements as a comma
+// separated list, in ascending order.
+func (f *FlagValue[T]) String() string {
+	// flag.PrintDefaults calls String on a zero FlagValue
+	if f == nil || f.s == nil {
+		return ""
+	}
+	text, err := f.s.MarshalText()
+	if err != nil {
+		return ""
+	}
+	return string(text)
+}
+
+// Get implements flag.Getter, it returns the Set.
+func (f *FlagValue[T]) Get() any {
+	return *f.s
+}
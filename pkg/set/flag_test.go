@@ -0,0 +1,63 @@
+package set
+
+import (
+	"context"
+	"flag"
+	"io"
+	"testing"
+
+	cliV2 "github.com/urfave/cli/v2"
+	cliV3 "github.com/urfave/cli/v3"
+	"gotest.tools/v3/assert"
+)
+
+func TestFlag(t *testing.T) {
+	regions := Of("default")
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Var(Flag(&regions), "regions", "regions to deploy to")
+
+	assert.NilError(t, fs.Parse([]string{"--regions", "b,a", "--regions", "c"}))
+	assert.DeepEqual(t, regions, Of("a", "b", "c"))
+	assert.Equal(t, fs.Lookup("regions").Value.String(), "a,b,c")
+	assert.DeepEqual(t, fs.Lookup("regions").Value.(flag.Getter).Get(), Of("a", "b", "c"))
+
+	err := fs.Parse([]string{"--regions", "d,d"})
+	assert.ErrorContains(t, err, "duplicate element d")
+}
+
+func TestFlagDefault(t *testing.T) {
+	regions := Of("b", "a")
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Var(Flag(&regions), "regions", "regions to deploy to")
+	assert.NilError(t, fs.Parse(nil))
+	assert.DeepEqual(t, regions, Of("a", "b"))
+	assert.Equal(t, fs.Lookup("regions").DefValue, "a,b")
+}
+
+func TestFlagCLIV2(t *testing.T) {
+	var regions Set[string]
+	app := &cliV2.App{
+		Flags: []cliV2.Flag{&cliV2.GenericFlag{Name: "regions", Value: Flag(&regions)}},
+		Action: func(*cliV2.Context) error {
+			return nil
+		},
+	}
+	assert.NilError(t, app.Run([]string{"app", "--regions", "a,b,c"}))
+	assert.DeepEqual(t, regions, Of("a", "b", "c"))
+}
+
+func TestFlagCLIV3(t *testing.T) {
+	var regions Set[string]
+	var got any
+	cmd := &cliV3.Command{
+		Flags: []cliV3.Flag{&cliV3.GenericFlag{Name: "regions", Value: Flag(&regions)}},
+		Action: func(_ context.Context, cmd *cliV3.Command) error {
+			got = cmd.Value("regions")
+			return nil
+		},
+	}
+	assert.NilError(t, cmd.Run(t.Context(), []string{"app", "--regions", "a,b,c"}))
+	assert.DeepEqual(t, regions, Of("a", "b", "c"))
+	assert.DeepEqual(t, got, Of("a", "b", "c"))
+}
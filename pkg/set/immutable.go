@@ -0,0 +1,390 @@
+// Copyright 2026 Outreach Corporation. All Rights Reserved.
+
+// Description: Implements a persistent set backed by a hash array mapped trie.
+
+package set
+
+import (
+	"fmt"
+	"hash/maphash"
+	"iter"
+	"math/bits"
+	"slices"
+	"strings"
+)
+
+// immutableSeed hashes the elements of every ImmutableSet.
+var immutableSeed = maphash.MakeSeed() // nolint:gochecknoglobals // Why: sets derived from one another must hash alike
+
+// hamtBits is the number of bits of the hash consumed at each level of the
+// trie, hamtMask extracts them.
+const (
+	hamtBits = 5
+	hamtMask = 1<<hamtBits - 1
+)
+
+// hamtNode is a node of the trie. bitmap has a bit set for each of the 32
+// possible slots which is in use, slots holds them in order.
+type hamtNode[T comparable] struct {
+	bitmap uint32
+	slots  []hamtSlot[T]
+}
+
+// hamtSlot is either a child node or a leaf holding the elements with the
+// given hash, of which there is more than one only on a hash collision.
+type hamtSlot[T comparable] struct {
+	child  *hamtNode[T]
+	hash   uint64
+	values []T
+}
+
+// ImmutableSet is a persistent set: it is never modified, and the methods
+// which would modify a Set return a new ImmutableSet instead. New sets
+// share most of their structure with the set they derive from, so Insert,
+// Delete and Contains cost O(log n) time and Insert and Delete O(log n)
+// memory, not a copy of the set.
+//
+// ImmutableSet has the same read and set algebra methods as Set. Insert,
+// InsertAll, Delete, DeleteAll and DeleteFunc return the new set, and there
+// are no in-place methods such as UnionWith or Clear. The zero value is an
+// empty set ready to use. ImmutableSet is safe for concurrent use since it
+// never changes.
+//
+//	a := set.ImmutableOf("x", "y")
+//	b := a.Insert("z") // a is still {x, y}
+type ImmutableSet[T comparable] struct {
+	root *hamtNode[T]
+	len  int
+}
+
+// ImmutableOf returns a new ImmutableSet containing the given values.
+func ImmutableOf[T comparable](v ...T) ImmutableSet[T] {
+	return CollectImmutable(slices.Values(v))
+}
+
+// CollectImmutable returns a new ImmutableSet containing the values
+// produced by seq, deduplicating them.
+func CollectImmutable[T comparable](seq iter.Seq[T]) ImmutableSet[T] {
+	return ImmutableSet[T]{}.InsertAll(seq)
+}
+
+// hashOf returns the hash of v in the trie.
+func hashOf[T comparable](v T) uint64 {
+	return maphash.Comparable(immutableSeed, v)
+}
+
+// slot returns the bit of the slot of h at shift, and the index of the
+// slot in n.slots if it is in use.
+func (n *hamtNode[T]) slot(h uint64, shift uint) (bit uint32, i int) {
+	bit = 1 << ((h >> shift) & hamtMask)
+	return bit, bits.OnesCount32(n.bitmap & (bit - 1))
+}
+
+// with returns a copy of n with slot i replaced by s.
+func (n *hamtNode[T]) with(i int, s hamtSlot[T]) *hamtNode[T] {
+	slots := slices.Clone(n.slots)
+	slots[i] = s
+	return &hamtNode[T]{bitmap: n.bitmap, slots: slots}
+}
+
+// contains reports whether v, whose hash is h, is in the trie rooted at n.
+func contains[T comparable](n *hamtNode[T], h uint64, v T) bool {
+	for shift := uint(0); n != nil; shift += hamtBits {
+		bit, i := n.slot(h, shift)
+		if n.bitmap&bit == 0 {
+			return false
+		}
+		s := n.slots[i]
+		if s.child == nil {
+			return s.hash == h && slices.Contains(s.values, v)
+		}
+		n = s.child
+	}
+	return false
+}
+
+// insert returns the trie rooted at n with v, whose hash is h, added and
+// whether it was added. n is returned as is if v was already present.
+func insert[T comparable](n *hamtNode[T], h uint64, shift uint, v T) (*hamtNode[T], bool) {
+	leaf := hamtSlot[T]{hash: h, values: []T{v}}
+	if n == nil {
+		return &hamtNode[T]{bitmap: 1 << ((h >> shift) & hamtMask), slots: []hamtSlot[T]{leaf}}, true
+	}
+
+	bit, i := n.slot(h, shift)
+	if n.bitmap&bit == 0 {
+		return &hamtNode[T]{bitmap: n.bitmap | bit, slots: slices.Insert(slices.Clone(n.slots), i, leaf)}, true
+	}
+
+	s := n.slots[i]
+	switch {
+	case s.child != nil:
+		child, added := insert(s.child, h, shift+hamtBits, v)
+		if !added {
+			return n, false
+		}
+		return n.with(i, hamtSlot[T]{child: child}), true
+	case s.hash == h:
+		if slices.Contains(s.values, v) {
+			return n, false
+		}
+		return n.with(i, hamtSlot[T]{hash: h, values: append(slices.Clone(s.values), v)}), true
+	default:
+		return n.with(i, hamtSlot[T]{child: split(s, leaf, shift+hamtBits)}), true
+	}
+}
+
+// split returns a node holding the leaves a and b, which have different
+// hashes, from shift on.
+func split[T comparable](a, b hamtSlot[T], shift uint) *hamtNode[T] {
+	ia, ib := (a.hash>>shift)&hamtMask, (b.hash>>shift)&hamtMask
+	switch {
+	case ia == ib:
+		return &hamtNode[T]{bitmap: 1 << ia, slots: []hamtSlot[T]{{child: split(a, b, shift+hamtBits)}}}
+	case ia < ib:
+		return &hamtNode[T]{bitmap: 1<<ia | 1<<ib, slots: []hamtSlot[T]{a, b}}
+	default:
+		return &hamtNode[T]{bitmap: 1<<ia | 1<<ib, slots: []hamtSlot[T]{b, a}}
+	}
+}
+
+// remove returns the trie rooted at n without v, whose hash is h, and
+// whether it was removed. n is returned as is if v was not present, nil if
+// the trie is now empty.
+func remove[T comparable](n *hamtNode[T], h uint64, shift uint, v T) (*hamtNode[T], bool) {
+	if n == nil {
+		return nil, false
+	}
+	bit, i := n.slot(h, shift)
+	if n.bitmap&bit == 0 {
+		return n, false
+	}
+
+	s := n.slots[i]
+	if s.child != nil {
+		child, removed := remove(s.child, h, shift+hamtBits, v)
+		switch {
+		case !removed:
+			return n, false
+		case child == nil:
+			return n.without(bit, i), true
+		case len(child.slots) == 1 && child.slots[0].child == nil:
+			// a lone leaf moves up, so that the trie stays as shallow as
+			// if it had never been split
+			return n.with(i, child.slots[0]), true
+		default:
+			return n.with(i, hamtSlot[T]{child: child}), true
+		}
+	}
+
+	j := slices.Index(s.values, v)
+	if s.hash != h || j < 0 {
+		return n, false
+	}
+	if len(s.values) > 1 {
+		return n.with(i, hamtSlot[T]{hash: h, values: slices.Delete(slices.Clone(s.values), j, j+1)}), true
+	}
+	return n.without(bit, i), true
+}
+
+// without returns a copy of n without slot i, whose bit is bit, or nil if
+// it was the only slot.
+func (n *hamtNode[T]) without(bit uint32, i int) *hamtNode[T] {
+	if len(n.slots) == 1 {
+		return nil
+	}
+	return &hamtNode[T]{bitmap: n.bitmap &^ bit, slots: slices.Delete(slices.Clone(n.slots), i, i+1)}
+}
+
+// walk calls yield for every element of the trie rooted at n, until yield
+// returns false.
+func walk[T comparable](n *hamtNode[T], yield func(T) bool) bool {
+	if n == nil {
+		return true
+	}
+	for _, s := range n.slots {
+		if s.child != nil {
+			if !walk(s.child, yield) {
+				return false
+			}
+			continue
+		}
+		for _, v := range s.values {
+			if !yield(v) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Insert returns a set containing the elements of s and v. It returns s if
+// v is already present.
+func (s ImmutableSet[T]) Insert(v T) ImmutableSet[T] {
+	root, added := insert(s.root, hashOf(v), 0, v)
+	if !added {
+		return s
+	}
+	return ImmutableSet[T]{root: root, len: s.len + 1}
+}
+
+// InsertAll returns a set containing the elements of s and every value
+// produced by seq.
+func (s ImmutableSet[T]) InsertAll(seq iter.Seq[T]) ImmutableSet[T] {
+	for v := range seq {
+		s = s.Insert(v)
+	}
+	return s
+}
+
+// Delete returns a set containing the elements of s but v. It returns s if
+// v is not present.
+func (s ImmutableSet[T]) Delete(v T) ImmutableSet[T] {
+	root, removed := remove(s.root, hashOf(v), 0, v)
+	if !removed {
+		return s
+	}
+	return ImmutableSet[T]{root: root, len: s.len - 1}
+}
+
+// DeleteAll returns a set containing the elements of s but the values
+// produced by seq.
+func (s ImmutableSet[T]) DeleteAll(seq iter.Seq[T]) ImmutableSet[T] {
+	for v := range seq {
+		s = s.Delete(v)
+	}
+	return s
+}
+
+// DeleteFunc returns a set containing the elements of s for which f
+// reports false.
+func (s ImmutableSet[T]) DeleteFunc(f func(T) bool) ImmutableSet[T] {
+	r := s
+	for v := range s.All() {
+		if f(v) {
+			r = r.Delete(v)
+		}
+	}
+	return r
+}
+
+// Contains reports whether v is a member of s.
+func (s ImmutableSet[T]) Contains(v T) bool {
+	return contains(s.root, hashOf(v), v)
+}
+
+// ContainsAll reports whether every value produced by seq is a member of s.
+func (s ImmutableSet[T]) ContainsAll(seq iter.Seq[T]) bool {
+	for v := range seq {
+		if !s.Contains(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// ContainsAny reports whether any value produced by seq is a member of s.
+func (s ImmutableSet[T]) ContainsAny(seq iter.Seq[T]) bool {
+	for v := range seq {
+		if s.Contains(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// Len returns the number of elements in s.
+func (s ImmutableSet[T]) Len() int {
+	return s.len
+}
+
+// String returns a "{a, b, c}"-style representation of s, sorted like
+// Set.String.
+func (s ImmutableSet[T]) String() string {
+	elems := make([]string, 0, s.len)
+	for v := range s.All() {
+		elems = append(elems, fmt.Sprint(v))
+	}
+	slices.Sort(elems)
+	return "{" + strings.Join(elems, ", ") + "}"
+}
+
+// All returns an iterator over the elements of s, in unspecified (but
+// stable) order.
+func (s ImmutableSet[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		walk(s.root, yield)
+	}
+}
+
+// Union returns a set containing every element of s and o.
+func (s ImmutableSet[T]) Union(o ImmutableSet[T]) ImmutableSet[T] {
+	// insert the elements of the smaller set into the bigger one
+	if o.len > s.len {
+		s, o = o, s
+	}
+	return s.InsertAll(o.All())
+}
+
+// Intersection returns a set containing the elements present in both s and
+// o.
+func (s ImmutableSet[T]) Intersection(o ImmutableSet[T]) ImmutableSet[T] {
+	small, big := s, o
+	if o.len < s.len {
+		small, big = o, s
+	}
+	return small.DeleteFunc(func(v T) bool { return !big.Contains(v) })
+}
+
+// Difference returns a set containing the elements of s that are not in o.
+func (s ImmutableSet[T]) Difference(o ImmutableSet[T]) ImmutableSet[T] {
+	if o.len < s.len {
+		return s.DeleteAll(o.All())
+	}
+	return s.DeleteFunc(o.Contains)
+}
+
+// SymmetricDifference returns a set containing the elements that are in
+// exactly one of s or o.
+func (s ImmutableSet[T]) SymmetricDifference(o ImmutableSet[T]) ImmutableSet[T] {
+	return s.Difference(o).Union(o.Difference(s))
+}
+
+// Intersects reports whether s and o share at least one element.
+func (s ImmutableSet[T]) Intersects(o ImmutableSet[T]) bool {
+	small, big := s, o
+	if o.len < s.len {
+		small, big = o, s
+	}
+	return big.ContainsAny(small.All())
+}
+
+// Equal reports whether s and o contain the same elements.
+func (s ImmutableSet[T]) Equal(o ImmutableSet[T]) bool {
+	return s.len == o.len && (s.root == o.root || o.ContainsAll(s.All()))
+}
+
+// Slice returns the elements of s as a slice, in unspecified order. It
+// returns nil if s is empty.
+func (s ImmutableSet[T]) Slice() []T {
+	return slices.Collect(s.All())
+}
+
+// SubsetOf reports whether every element of s is also in o.
+func (s ImmutableSet[T]) SubsetOf(o ImmutableSet[T]) bool {
+	return s.len <= o.len && o.ContainsAll(s.All())
+}
+
+// SupersetOf reports whether every element of o is also in s.
+func (s ImmutableSet[T]) SupersetOf(o ImmutableSet[T]) bool {
+	return o.SubsetOf(s)
+}
+
+// ToSet returns the elements of s as a Set.
+func (s ImmutableSet[T]) ToSet() Set[T] {
+	r := make(Set[T], s.len)
+	for v := range s.All() {
+		r[v] = struct{}{}
+	}
+	return r
+}
@@ -0,0 +1,122 @@
+package set
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestImmutableSetOps(t *testing.T) {
+	sorted := func(s ImmutableSet[int]) []int { return slices.Sorted(s.All()) }
+
+	s := ImmutableOf(1, 2, 3)
+	assert.Equal(t, s.Insert(1), s)
+	s = s.Insert(4).InsertAll(slices.Values([]int{4, 5}))
+	assert.DeepEqual(t, sorted(s), []int{1, 2, 3, 4, 5})
+	assert.Equal(t, s.Delete(9), s)
+	s = s.Delete(5).DeleteAll(slices.Values([]int{4, 9}))
+	assert.Assert(t, s.ContainsAll(slices.Values([]int{1, 2, 3})))
+	assert.Assert(t, s.ContainsAny(slices.Values([]int{0, 3})))
+	assert.Assert(t, !s.Contains(4))
+	assert.Equal(t, s.Len(), 3)
+	assert.Equal(t, s.String(), "{1, 2, 3}")
+	assert.DeepEqual(t, slices.Sorted(slices.Values(s.Slice())), []int{1, 2, 3})
+	assert.DeepEqual(t, sorted(s.DeleteFunc(func(v int) bool { return v > 1 })), []int{1})
+
+	a, b := ImmutableOf(1, 2, 3), ImmutableOf(2, 3, 4)
+	assert.DeepEqual(t, sorted(a.Union(b)), []int{1, 2, 3, 4})
+	assert.DeepEqual(t, sorted(a.Intersection(b)), []int{2, 3})
+	assert.DeepEqual(t, sorted(a.Difference(b)), []int{1})
+	assert.DeepEqual(t, sorted(a.Difference(ImmutableOf(3))), []int{1, 2})
+	assert.DeepEqual(t, sorted(a.SymmetricDifference(b)), []int{1, 4})
+	assert.Assert(t, a.Intersects(b))
+	assert.Assert(t, !a.Intersects(ImmutableOf(7)))
+	assert.Assert(t, ImmutableOf(2, 3).SubsetOf(a))
+	assert.Assert(t, a.SupersetOf(ImmutableOf(2, 3)))
+	assert.Assert(t, !a.SubsetOf(b))
+	assert.Assert(t, a.Equal(ImmutableOf(3, 2, 1)))
+	assert.Assert(t, !a.Equal(b))
+	assert.DeepEqual(t, a.ToSet(), Of(1, 2, 3))
+
+	// none of the above changed a or b
+	assert.DeepEqual(t, sorted(a), []int{1, 2, 3})
+	assert.DeepEqual(t, sorted(b), []int{2, 3, 4})
+}
+
+func TestImmutableSetPersistent(t *testing.T) {
+	// every version must keep its elements while later versions are
+	// derived from it, through node splits and collapses
+	var versions []ImmutableSet[int]
+	want := Set[int]{}
+	var wants []Set[int]
+
+	s := ImmutableSet[int]{}
+	for range 5000 {
+		v := rand.IntN(2000)
+		if rand.IntN(3) == 0 {
+			s = s.Delete(v)
+			want.Delete(v)
+		} else {
+			s = s.Insert(v)
+			want.Insert(v)
+		}
+		versions = append(versions, s)
+		wants = append(wants, want.Clone())
+	}
+
+	for i, s := range versions {
+		assert.Equal(t, s.Len(), wants[i].Len())
+		assert.DeepEqual(t, s.ToSet(), wants[i])
+	}
+
+	// deleting everything leaves an empty trie
+	s = s.DeleteAll(s.All())
+	assert.Equal(t, s.Len(), 0)
+	assert.Assert(t, s.root == nil)
+}
+
+func TestImmutableSetCollisions(t *testing.T) {
+	// elements sharing a hash share a leaf, elements sharing a prefix of
+	// their hash are split into child nodes
+	var n *hamtNode[string]
+	var added bool
+	for _, e := range []struct {
+		v string
+		h uint64
+	}{{"a", 1}, {"b", 1}, {"c", 1 | 1<<60}, {"d", 2}} {
+		n, added = insert(n, e.h, 0, e.v)
+		assert.Assert(t, added, e.v)
+	}
+	_, added = insert(n, 1, 0, "b")
+	assert.Assert(t, !added)
+
+	assert.Assert(t, contains(n, 1, "a"))
+	assert.Assert(t, contains(n, 1, "b"))
+	assert.Assert(t, contains(n, 1|1<<60, "c"))
+	assert.Assert(t, !contains(n, 1|1<<60, "a"))
+	assert.Assert(t, !contains(n, 1, "c"))
+
+	var removed bool
+	n, removed = remove(n, 1, 0, "a")
+	assert.Assert(t, removed)
+	n, removed = remove(n, 1|1<<60, 0, "c")
+	assert.Assert(t, removed)
+	assert.Assert(t, contains(n, 1, "b"))
+	assert.Assert(t, !contains(n, 1, "a"))
+
+	// the lone leaf left moved back up to the root
+	assert.Equal(t, len(n.slots), 2)
+	assert.Assert(t, n.slots[0].child == nil)
+}
+
+func TestImmutableSetZeroValue(t *testing.T) {
+	var s ImmutableSet[int]
+	assert.Assert(t, !s.Contains(1))
+	assert.Equal(t, s.Delete(1).Len(), 0)
+	assert.Equal(t, s.String(), "{}")
+	assert.Assert(t, s.Slice() == nil)
+	assert.Assert(t, s.Equal(ImmutableOf[int]()))
+	assert.DeepEqual(t, s.Insert(1).ToSet(), Of(1))
+}
@@ -0,0 +1,459 @@
+// Copyright 2026 Outreach Corporation. All Rights Reserved.
+
+// Description: Implements an ordered set backed by a skip list.
+
+package set
+
+import (
+	"cmp"
+	"fmt"
+	"iter"
+	"math/bits"
+	"math/rand/v2"
+	"strings"
+)
+
+// maxLevel is the maximum number of levels of the skip list, enough for
+// 4^32 elements with a 1/4 probability of promotion.
+const maxLevel = 32
+
+// node is an element of the skip list. next[i] is the next node at level i.
+type node[T cmp.Ordered] struct {
+	value T
+	next  []*node[T]
+}
+
+// newNode returns a node for v with a random number of levels.
+func newNode[T cmp.Ordered](v T) *node[T] {
+	// each additional level has a 1/4 probability
+	level := min(bits.TrailingZeros64(rand.Uint64())/2+1, maxLevel)
+	return &node[T]{value: v, next: make([]*node[T], level)}
+}
+
+// OrderedSet is a set of ordered elements which iterates in ascending
+// order, backed by a skip list. Insert, Delete and Contains cost
+// O(log n); Min, Max and range queries are cheap, unlike with Sorted,
+// which copies and sorts a Set every time.
+//
+// OrderedSet has the same methods as Set, so one can be swapped for the
+// other. The zero value is an empty set ready to use. OrderedSet is not
+// safe for concurrent use.
+type OrderedSet[T cmp.Ordered] struct {
+	head  node[T]
+	level int
+	len   int
+}
+
+// OrderedOf returns a new OrderedSet containing the given values.
+func OrderedOf[T cmp.Ordered](v ...T) *OrderedSet[T] {
+	s := &OrderedSet[T]{}
+	for _, x := range v {
+		s.Insert(x)
+	}
+	return s
+}
+
+// CollectOrdered returns a new OrderedSet containing the values produced
+// by seq, deduplicating them.
+func CollectOrdered[T cmp.Ordered](seq iter.Seq[T]) *OrderedSet[T] {
+	s := &OrderedSet[T]{}
+	s.InsertAll(seq)
+	return s
+}
+
+// init allocates the levels of the head node.
+func (s *OrderedSet[T]) init() {
+	if s.head.next == nil {
+		s.head.next = make([]*node[T], maxLevel)
+	}
+}
+
+// first returns the node holding the smallest element, or nil.
+func (s *OrderedSet[T]) first() *node[T] {
+	if s.head.next == nil {
+		return nil
+	}
+	return s.head.next[0]
+}
+
+// seek fills update with the last node before v at each level and returns
+// the first node not before v, or nil.
+func (s *OrderedSet[T]) seek(v T, update *[maxLevel]*node[T]) *node[T] {
+	x := &s.head
+	for i := s.level - 1; i >= 0; i-- {
+		for x.next[i] != nil && cmp.Less(x.next[i].value, v) {
+			x = x.next[i]
+		}
+		if update != nil {
+			update[i] = x
+		}
+	}
+	return x.next[0]
+}
+
+// ceiling returns the node holding the smallest element not less than v,
+// or nil.
+func (s *OrderedSet[T]) ceiling(v T) *node[T] {
+	if s.head.next == nil {
+		return nil
+	}
+	return s.seek(v, nil)
+}
+
+// Insert adds v to s and reports whether the set grew (i.e. v was not
+// already present).
+func (s *OrderedSet[T]) Insert(v T) bool {
+	s.init()
+	var update [maxLevel]*node[T]
+	if n := s.seek(v, &update); n != nil && n.value == v {
+		return false
+	}
+
+	n := newNode(v)
+	for i := s.level; i < len(n.next); i++ {
+		update[i] = &s.head
+	}
+	s.level = max(s.level, len(n.next))
+	for i := range n.next {
+		n.next[i] = update[i].next[i]
+		update[i].next[i] = n
+	}
+	s.len++
+	return true
+}
+
+// InsertAll adds every value produced by seq to s and reports whether the
+// set grew.
+func (s *OrderedSet[T]) InsertAll(seq iter.Seq[T]) bool {
+	grew := false
+	for v := range seq {
+		if s.Insert(v) {
+			grew = true
+		}
+	}
+	return grew
+}
+
+// Delete removes v from s and reports whether it was present.
+func (s *OrderedSet[T]) Delete(v T) bool {
+	if s.head.next == nil {
+		return false
+	}
+	var update [maxLevel]*node[T]
+	n := s.seek(v, &update)
+	if n == nil || n.value != v {
+		return false
+	}
+
+	// n.next is left as is, so that iterators positioned on n can move on
+	for i := range n.next {
+		update[i].next[i] = n.next[i]
+	}
+	for s.level > 0 && s.head.next[s.level-1] == nil {
+		s.level--
+	}
+	s.len--
+	return true
+}
+
+// DeleteAll removes every value produced by seq from s and reports whether
+// the set shrank.
+func (s *OrderedSet[T]) DeleteAll(seq iter.Seq[T]) bool {
+	shrank := false
+	for v := range seq {
+		if s.Delete(v) {
+			shrank = true
+		}
+	}
+	return shrank
+}
+
+// DeleteFunc removes every element for which f reports true and reports
+// whether the set shrank.
+func (s *OrderedSet[T]) DeleteFunc(f func(T) bool) bool {
+	before := s.len
+	for v := range s.All() {
+		if f(v) {
+			s.Delete(v)
+		}
+	}
+	return s.len != before
+}
+
+// Contains reports whether v is a member of s.
+func (s *OrderedSet[T]) Contains(v T) bool {
+	n := s.ceiling(v)
+	return n != nil && n.value == v
+}
+
+// ContainsAll reports whether every value produced by seq is a member of s.
+func (s *OrderedSet[T]) ContainsAll(seq iter.Seq[T]) bool {
+	for v := range seq {
+		if !s.Contains(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// ContainsAny reports whether any value produced by seq is a member of s.
+func (s *OrderedSet[T]) ContainsAny(seq iter.Seq[T]) bool {
+	for v := range seq {
+		if s.Contains(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// Len returns the number of elements in s.
+func (s *OrderedSet[T]) Len() int {
+	return s.len
+}
+
+// Clear removes all elements from s.
+func (s *OrderedSet[T]) Clear() {
+	*s = OrderedSet[T]{}
+}
+
+// Clone returns a copy of s.
+func (s *OrderedSet[T]) Clone() *OrderedSet[T] {
+	b := newOrderedBuilder[T]()
+	for v := range s.All() {
+		b.append(v)
+	}
+	return b.s
+}
+
+// String returns a "{a, b, c}"-style representation of s, in ascending
+// order.
+func (s *OrderedSet[T]) String() string {
+	elems := make([]string, 0, s.len)
+	for v := range s.All() {
+		elems = append(elems, fmt.Sprint(v))
+	}
+	return "{" + strings.Join(elems, ", ") + "}"
+}
+
+// All returns an iterator over the elements of s, in ascending order.
+// Deleting elements during iteration is safe.
+func (s *OrderedSet[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for n := s.first(); n != nil; n = n.next[0] {
+			if !yield(n.value) {
+				return
+			}
+		}
+	}
+}
+
+// Range returns an iterator over the elements of s greater than or equal
+// to from and less than to, in ascending order.
+func (s *OrderedSet[T]) Range(from, to T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for n := s.ceiling(from); n != nil && cmp.Less(n.value, to); n = n.next[0] {
+			if !yield(n.value) {
+				return
+			}
+		}
+	}
+}
+
+// Min returns the smallest element of s, or false if s is empty.
+func (s *OrderedSet[T]) Min() (T, bool) {
+	if n := s.first(); n != nil {
+		return n.value, true
+	}
+	var zero T
+	return zero, false
+}
+
+// Max returns the largest element of s, or false if s is empty.
+func (s *OrderedSet[T]) Max() (T, bool) {
+	x := &s.head
+	for i := s.level - 1; i >= 0; i-- {
+		for x.next[i] != nil {
+			x = x.next[i]
+		}
+	}
+	if x == &s.head {
+		var zero T
+		return zero, false
+	}
+	return x.value, true
+}
+
+// Union returns a new set containing every element of s and o.
+func (s *OrderedSet[T]) Union(o *OrderedSet[T]) *OrderedSet[T] {
+	b := newOrderedBuilder[T]()
+	merge(s, o, func(v T, _, _ bool) { b.append(v) })
+	return b.s
+}
+
+// UnionWith adds every element of o to s in place.
+func (s *OrderedSet[T]) UnionWith(o *OrderedSet[T]) {
+	if s == o {
+		return
+	}
+	s.InsertAll(o.All())
+}
+
+// Intersection returns a new set containing the elements present in both s
+// and o.
+func (s *OrderedSet[T]) Intersection(o *OrderedSet[T]) *OrderedSet[T] {
+	b := newOrderedBuilder[T]()
+	merge(s, o, func(v T, inS, inO bool) {
+		if inS && inO {
+			b.append(v)
+		}
+	})
+	return b.s
+}
+
+// IntersectionWith removes every element of s that is not also in o.
+func (s *OrderedSet[T]) IntersectionWith(o *OrderedSet[T]) {
+	s.DeleteFunc(func(v T) bool { return !o.Contains(v) })
+}
+
+// Difference returns a new set containing the elements of s that are not in
+// o.
+func (s *OrderedSet[T]) Difference(o *OrderedSet[T]) *OrderedSet[T] {
+	b := newOrderedBuilder[T]()
+	merge(s, o, func(v T, inS, inO bool) {
+		if inS && !inO {
+			b.append(v)
+		}
+	})
+	return b.s
+}
+
+// DifferenceWith removes every element of o from s.
+func (s *OrderedSet[T]) DifferenceWith(o *OrderedSet[T]) {
+	if s == o {
+		s.Clear()
+		return
+	}
+	s.DeleteAll(o.All())
+}
+
+// SymmetricDifference returns a new set containing the elements that are in
+// exactly one of s or o.
+func (s *OrderedSet[T]) SymmetricDifference(o *OrderedSet[T]) *OrderedSet[T] {
+	b := newOrderedBuilder[T]()
+	merge(s, o, func(v T, inS, inO bool) {
+		if inS != inO {
+			b.append(v)
+		}
+	})
+	return b.s
+}
+
+// SymmetricDifferenceWith updates s in place to contain the elements that
+// are in exactly one of s or o.
+func (s *OrderedSet[T]) SymmetricDifferenceWith(o *OrderedSet[T]) {
+	*s = *s.SymmetricDifference(o)
+}
+
+// Intersects reports whether s and o share at least one element.
+func (s *OrderedSet[T]) Intersects(o *OrderedSet[T]) bool {
+	small, big := s, o
+	if o.Len() < s.Len() {
+		small, big = o, s
+	}
+	return big.ContainsAny(small.All())
+}
+
+// Equal reports whether s and o contain the same elements.
+func (s *OrderedSet[T]) Equal(o *OrderedSet[T]) bool {
+	if s.Len() != o.Len() {
+		return false
+	}
+	a, b := s.first(), o.first()
+	for ; a != nil; a, b = a.next[0], b.next[0] {
+		if a.value != b.value {
+			return false
+		}
+	}
+	return true
+}
+
+// Slice returns the elements of s as a slice, in ascending order. It
+// returns nil if s is empty.
+func (s *OrderedSet[T]) Slice() []T {
+	if s.len == 0 {
+		return nil
+	}
+	r := make([]T, 0, s.len)
+	for v := range s.All() {
+		r = append(r, v)
+	}
+	return r
+}
+
+// SubsetOf reports whether every element of s is also in o.
+func (s *OrderedSet[T]) SubsetOf(o *OrderedSet[T]) bool {
+	return s.Len() <= o.Len() && o.ContainsAll(s.All())
+}
+
+// SupersetOf reports whether every element of o is also in s.
+func (s *OrderedSet[T]) SupersetOf(o *OrderedSet[T]) bool {
+	return o.SubsetOf(s)
+}
+
+// ToSet returns the elements of s as a Set.
+func (s *OrderedSet[T]) ToSet() Set[T] {
+	r := make(Set[T], s.len)
+	for v := range s.All() {
+		r[v] = struct{}{}
+	}
+	return r
+}
+
+// merge walks s and o in ascending order, calling f once for each element
+// of either set with whether it is in s and in o.
+func merge[T cmp.Ordered](s, o *OrderedSet[T], f func(v T, inS, inO bool)) {
+	a, b := s.first(), o.first()
+	for a != nil || b != nil {
+		switch {
+		case b == nil || (a != nil && cmp.Less(a.value, b.value)):
+			f(a.value, true, false)
+			a = a.next[0]
+		case a == nil || cmp.Less(b.value, a.value):
+			f(b.value, false, true)
+			b = b.next[0]
+		default:
+			f(a.value, true, true)
+			a, b = a.next[0], b.next[0]
+		}
+	}
+}
+
+// orderedBuilder builds an OrderedSet from elements appended in ascending
+// order, in O(1) per element.
+type orderedBuilder[T cmp.Ordered] struct {
+	s *OrderedSet[T]
+
+	// tail is the last node at each level
+	tail [maxLevel]*node[T]
+}
+
+// newOrderedBuilder returns a builder of a new set.
+func newOrderedBuilder[T cmp.Ordered]() *orderedBuilder[T] {
+	b := &orderedBuilder[T]{s: &OrderedSet[T]{}}
+	b.s.init()
+	for i := range b.tail {
+		b.tail[i] = &b.s.head
+	}
+	return b
+}
+
+// append adds v, which must be greater than every element in the set.
+func (b *orderedBuilder[T]) append(v T) {
+	n := newNode(v)
+	for i := range n.next {
+		b.tail[i].next[i] = n
+		b.tail[i] = n
+	}
+	b.s.level = max(b.s.level, len(n.next))
+	b.s.len++
+}
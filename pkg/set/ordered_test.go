@@ -0,0 +1,157 @@
+package set
+
+import (
+	"iter"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+// setOps is the method set shared by Set, OrderedSet and SyncSet, where S
+// is the type of the set itself.
+type setOps[T any, S any] interface {
+	Insert(v T) bool
+	InsertAll(seq iter.Seq[T]) bool
+	Delete(v T) bool
+	DeleteAll(seq iter.Seq[T]) bool
+	DeleteFunc(f func(T) bool) bool
+	Contains(v T) bool
+	ContainsAll(seq iter.Seq[T]) bool
+	ContainsAny(seq iter.Seq[T]) bool
+	Len() int
+	Clear()
+	Clone() S
+	String() string
+	All() iter.Seq[T]
+	Union(o S) S
+	UnionWith(o S)
+	Intersection(o S) S
+	IntersectionWith(o S)
+	Difference(o S) S
+	DifferenceWith(o S)
+	SymmetricDifference(o S) S
+	SymmetricDifferenceWith(o S)
+	Intersects(o S) bool
+	Equal(o S) bool
+	Slice() []T
+	SubsetOf(o S) bool
+	SupersetOf(o S) bool
+}
+
+// testSetOps checks that a set type behaves like Set.
+func testSetOps[S setOps[int, S]](t *testing.T, of func(v ...int) S) {
+	sorted := func(s S) []int { return slices.Sorted(s.All()) }
+
+	s := of(1, 2, 3)
+	assert.Assert(t, !s.Insert(1))
+	assert.Assert(t, s.Insert(4))
+	assert.Assert(t, s.InsertAll(slices.Values([]int{4, 5})))
+	assert.Assert(t, s.Delete(5))
+	assert.Assert(t, !s.Delete(5))
+	assert.Assert(t, s.DeleteAll(slices.Values([]int{4, 9})))
+	assert.Assert(t, s.ContainsAll(slices.Values([]int{1, 2, 3})))
+	assert.Assert(t, s.ContainsAny(slices.Values([]int{0, 3})))
+	assert.Assert(t, !s.Contains(4))
+	assert.Equal(t, s.Len(), 3)
+	assert.Equal(t, s.String(), "{1, 2, 3}")
+	assert.DeepEqual(t, slices.Sorted(slices.Values(s.Slice())), []int{1, 2, 3})
+
+	a, b := of(1, 2, 3), of(2, 3, 4)
+	assert.DeepEqual(t, sorted(a.Union(b)), []int{1, 2, 3, 4})
+	assert.DeepEqual(t, sorted(a.Intersection(b)), []int{2, 3})
+	assert.DeepEqual(t, sorted(a.Difference(b)), []int{1})
+	assert.DeepEqual(t, sorted(a.SymmetricDifference(b)), []int{1, 4})
+	assert.Assert(t, a.Intersects(b))
+	assert.Assert(t, !a.Intersects(of(7)))
+	assert.Assert(t, of(2, 3).SubsetOf(a))
+	assert.Assert(t, a.SupersetOf(of(2, 3)))
+	assert.Assert(t, !a.SubsetOf(b))
+	assert.Assert(t, a.Equal(of(3, 2, 1)))
+	assert.Assert(t, !a.Equal(b))
+
+	c := a.Clone()
+	c.UnionWith(b)
+	assert.DeepEqual(t, sorted(c), []int{1, 2, 3, 4})
+	assert.DeepEqual(t, sorted(a), []int{1, 2, 3}) // Clone copies the set
+	c.IntersectionWith(b)
+	assert.DeepEqual(t, sorted(c), []int{2, 3, 4})
+	c.DifferenceWith(of(4))
+	assert.DeepEqual(t, sorted(c), []int{2, 3})
+	c.SymmetricDifferenceWith(of(3, 5))
+	assert.DeepEqual(t, sorted(c), []int{2, 5})
+	assert.Assert(t, c.DeleteFunc(func(v int) bool { return v > 2 }))
+	assert.DeepEqual(t, sorted(c), []int{2})
+	c.Clear()
+	assert.Equal(t, c.Len(), 0)
+	assert.Assert(t, c.Slice() == nil)
+}
+
+func TestSetOps(t *testing.T) {
+	t.Run("Set", func(t *testing.T) { testSetOps(t, Of[int]) })
+	t.Run("OrderedSet", func(t *testing.T) { testSetOps(t, OrderedOf[int]) })
+	t.Run("SyncSet", func(t *testing.T) { testSetOps(t, NewSyncSet[int]) })
+}
+
+func TestOrderedSetOrder(t *testing.T) {
+	values := rand.Perm(1000)
+	s := CollectOrdered(slices.Values(values))
+	want := slices.Sorted(slices.Values(values))
+	assert.DeepEqual(t, slices.Collect(s.All()), want)
+	assert.DeepEqual(t, s.Slice(), want)
+
+	// delete half of the values, some during iteration
+	for v := range s.All() {
+		if v%2 == 0 {
+			assert.Assert(t, s.Delete(v))
+		}
+	}
+	want = slices.DeleteFunc(want, func(v int) bool { return v%2 == 0 })
+	assert.DeepEqual(t, slices.Collect(s.All()), want)
+	assert.Equal(t, s.Len(), len(want))
+
+	minV, ok := s.Min()
+	assert.Assert(t, ok)
+	assert.Equal(t, minV, 1)
+	maxV, ok := s.Max()
+	assert.Assert(t, ok)
+	assert.Equal(t, maxV, 999)
+}
+
+func TestOrderedSetRange(t *testing.T) {
+	s := OrderedOf("apple", "banana", "cherry", "date", "fig")
+	assert.DeepEqual(t, slices.Collect(s.Range("b", "d")), []string{"banana", "cherry"})
+	assert.DeepEqual(t, slices.Collect(s.Range("cherry", "fig")), []string{"cherry", "date"})
+	assert.DeepEqual(t, slices.Collect(s.Range("g", "z")), []string(nil))
+
+	for v := range s.Range("a", "z") {
+		if v == "banana" {
+			break
+		}
+	}
+}
+
+func TestOrderedSetZeroValue(t *testing.T) {
+	var s OrderedSet[int]
+	assert.Assert(t, !s.Contains(1))
+	assert.Assert(t, !s.Delete(1))
+	_, ok := s.Min()
+	assert.Assert(t, !ok)
+	_, ok = s.Max()
+	assert.Assert(t, !ok)
+	assert.Equal(t, s.String(), "{}")
+	assert.Assert(t, s.Equal(&OrderedSet[int]{}))
+
+	assert.Assert(t, s.Insert(1))
+	assert.DeepEqual(t, s.ToSet(), Of(1))
+}
+
+func TestOrderedSetSelf(t *testing.T) {
+	s := OrderedOf(1, 2)
+	s.UnionWith(s)
+	s.IntersectionWith(s)
+	assert.DeepEqual(t, s.Slice(), []int{1, 2})
+	s.DifferenceWith(s)
+	assert.Equal(t, s.Len(), 0)
+}
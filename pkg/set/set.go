@@ -16,7 +16,18 @@
 //	z.Contains("x") // false, no panic
 //	z.Insert("x")   // panics: assignment to entry in nil map
 //
-// Set is not safe for concurrent use.
+// Set is not safe for concurrent use. SyncSet wraps a Set with a lock, and
+// OrderedSet keeps cmp.Ordered elements sorted for ordered iteration, Min, Max
+// and range queries. Both have the same methods as Set, taking and returning
+// their own type, so they can be swapped in. ImmutableSet is a persistent set:
+// it never changes, Insert and Delete return a new set sharing most of its
+// structure with the original, which makes it cheap to keep and share
+// versions of a set without copying or locking.
+//
+// Sets encode to JSON, YAML and text as sorted lists, so they can be used in
+// configuration files loaded with cfg.Load, and decoding rejects duplicate
+// elements with a *DuplicateError. Flag adapts a Set to a command line flag
+// such as --regions a,b,c, for the flag package and urfave/cli.
 //
 // Coming from deckarep/golang-set, the migration is largely a rename:
 //
@@ -296,6 +307,17 @@ func (s Set[T]) Slice() []T {
 	return slices.Collect(s.All())
 }
 
+// SubsetOf reports whether every element of s is also in o, see Subset.
+func (s Set[T]) SubsetOf(o Set[T]) bool {
+	return Subset(s, o)
+}
+
+// SupersetOf reports whether every element of o is also in s, see
+// Superset.
+func (s Set[T]) SupersetOf(o Set[T]) bool {
+	return Superset(s, o)
+}
+
 // Subset reports whether every element of a is also in b.
 func Subset[T comparable](a, b Set[T]) bool {
 	if len(a) > len(b) {
@@ -0,0 +1,244 @@
+// Copyright 2026 Outreach Corporation. All Rights Reserved.
+
+// Description: Implements a set which is safe for concurrent use.
+
+package set
+
+import (
+	"iter"
+	"sync"
+)
+
+// SyncSet is a Set which is safe for concurrent use, guarded by a
+// sync.RWMutex.
+//
+// SyncSet has the same methods as Set, so one can be swapped for the
+// other. Operations taking another SyncSet work on a copy of it, so that
+// they never hold the locks of both sets. The zero value is an empty set
+// ready to use.
+type SyncSet[T comparable] struct {
+	mu sync.RWMutex
+	s  Set[T]
+}
+
+// NewSyncSet returns a new SyncSet containing the given values.
+func NewSyncSet[T comparable](v ...T) *SyncSet[T] {
+	return &SyncSet[T]{s: Of(v...)}
+}
+
+// wrap returns a SyncSet owning s.
+func wrap[T comparable](s Set[T]) *SyncSet[T] {
+	return &SyncSet[T]{s: s}
+}
+
+// write runs f with the set locked for writing, allocating it if needed.
+func (s *SyncSet[T]) write(f func(s Set[T])) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.s == nil {
+		s.s = make(Set[T])
+	}
+	f(s.s)
+}
+
+// Insert adds v to s and reports whether the set grew (i.e. v was not
+// already present).
+func (s *SyncSet[T]) Insert(v T) (grew bool) {
+	s.write(func(set Set[T]) { grew = set.Insert(v) })
+	return grew
+}
+
+// InsertAll adds every value produced by seq to s and reports whether the
+// set grew. The set is locked while seq is consumed.
+func (s *SyncSet[T]) InsertAll(seq iter.Seq[T]) (grew bool) {
+	s.write(func(set Set[T]) { grew = set.InsertAll(seq) })
+	return grew
+}
+
+// Delete removes v from s and reports whether it was present.
+func (s *SyncSet[T]) Delete(v T) (shrank bool) {
+	s.write(func(set Set[T]) { shrank = set.Delete(v) })
+	return shrank
+}
+
+// DeleteAll removes every value produced by seq from s and reports whether
+// the set shrank. The set is locked while seq is consumed.
+func (s *SyncSet[T]) DeleteAll(seq iter.Seq[T]) (shrank bool) {
+	s.write(func(set Set[T]) { shrank = set.DeleteAll(seq) })
+	return shrank
+}
+
+// DeleteFunc removes every element for which f reports true and reports
+// whether the set shrank. f is called with the set locked and must not
+// use it.
+func (s *SyncSet[T]) DeleteFunc(f func(T) bool) (shrank bool) {
+	s.write(func(set Set[T]) { shrank = set.DeleteFunc(f) })
+	return shrank
+}
+
+// Contains reports whether v is a member of s.
+func (s *SyncSet[T]) Contains(v T) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.s.Contains(v)
+}
+
+// ContainsAll reports whether every value produced by seq is a member of s.
+// The set is locked while seq is consumed.
+func (s *SyncSet[T]) ContainsAll(seq iter.Seq[T]) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.s.ContainsAll(seq)
+}
+
+// ContainsAny reports whether any value produced by seq is a member of s.
+// The set is locked while seq is consumed.
+func (s *SyncSet[T]) ContainsAny(seq iter.Seq[T]) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.s.ContainsAny(seq)
+}
+
+// Len returns the number of elements in s.
+func (s *SyncSet[T]) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.s.Len()
+}
+
+// Clear removes all elements from s.
+func (s *SyncSet[T]) Clear() {
+	s.write(Set[T].Clear)
+}
+
+// Clone returns a copy of s.
+func (s *SyncSet[T]) Clone() *SyncSet[T] {
+	return wrap(s.ToSet())
+}
+
+// String returns a "{a, b, c}"-style representation of s, see Set.String.
+func (s *SyncSet[T]) String() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.s.String()
+}
+
+// All returns an iterator over the elements of s, in unspecified order. It
+// iterates over a copy of the set taken when iteration starts, so the set
+// can be modified during iteration.
+func (s *SyncSet[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, v := range s.Slice() {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Union returns a new set containing every element of s and o.
+func (s *SyncSet[T]) Union(o *SyncSet[T]) *SyncSet[T] {
+	other := o.ToSet()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return wrap(s.s.Union(other))
+}
+
+// UnionWith adds every element of o to s in place.
+func (s *SyncSet[T]) UnionWith(o *SyncSet[T]) {
+	other := o.ToSet()
+	s.write(func(set Set[T]) { set.UnionWith(other) })
+}
+
+// Intersection returns a new set containing the elements present in both s
+// and o.
+func (s *SyncSet[T]) Intersection(o *SyncSet[T]) *SyncSet[T] {
+	other := o.ToSet()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return wrap(s.s.Intersection(other))
+}
+
+// IntersectionWith removes every element of s that is not also in o.
+func (s *SyncSet[T]) IntersectionWith(o *SyncSet[T]) {
+	other := o.ToSet()
+	s.write(func(set Set[T]) { set.IntersectionWith(other) })
+}
+
+// Difference returns a new set containing the elements of s that are not in
+// o.
+func (s *SyncSet[T]) Difference(o *SyncSet[T]) *SyncSet[T] {
+	other := o.ToSet()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return wrap(s.s.Difference(other))
+}
+
+// DifferenceWith removes every element of o from s.
+func (s *SyncSet[T]) DifferenceWith(o *SyncSet[T]) {
+	other := o.ToSet()
+	s.write(func(set Set[T]) { set.DifferenceWith(other) })
+}
+
+// SymmetricDifference returns a new set containing the elements that are in
+// exactly one of s or o.
+func (s *SyncSet[T]) SymmetricDifference(o *SyncSet[T]) *SyncSet[T] {
+	other := o.ToSet()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return wrap(s.s.SymmetricDifference(other))
+}
+
+// SymmetricDifferenceWith updates s in place to contain the elements that
+// are in exactly one of s or o.
+func (s *SyncSet[T]) SymmetricDifferenceWith(o *SyncSet[T]) {
+	other := o.ToSet()
+	s.write(func(set Set[T]) { set.SymmetricDifferenceWith(other) })
+}
+
+// Intersects reports whether s and o share at least one element.
+func (s *SyncSet[T]) Intersects(o *SyncSet[T]) bool {
+	other := o.ToSet()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.s.Intersects(other)
+}
+
+// Equal reports whether s and o contain the same elements.
+func (s *SyncSet[T]) Equal(o *SyncSet[T]) bool {
+	other := o.ToSet()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.s.Equal(other)
+}
+
+// Slice returns the elements of s as a slice, in unspecified order. It
+// returns nil if s is empty.
+func (s *SyncSet[T]) Slice() []T {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.s.Slice()
+}
+
+// SubsetOf reports whether every element of s is also in o.
+func (s *SyncSet[T]) SubsetOf(o *SyncSet[T]) bool {
+	other := o.ToSet()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return Subset(s.s, other)
+}
+
+// SupersetOf reports whether every element of o is also in s.
+func (s *SyncSet[T]) SupersetOf(o *SyncSet[T]) bool {
+	other := o.ToSet()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return Superset(s.s, other)
+}
+
+// ToSet returns a copy of the elements of s as a Set.
+func (s *SyncSet[T]) ToSet() Set[T] {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.s.Clone()
+}
@@ -0,0 +1,47 @@
+package set
+
+import (
+	"sync"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestSyncSetConcurrent(t *testing.T) {
+	var s SyncSet[int]
+	other := NewSyncSet(0, 1)
+
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Go(func() {
+			for j := range 100 {
+				s.Insert(i*100 + j)
+				s.Contains(j)
+				s.UnionWith(other)
+				other.Intersects(&s)
+				for range s.All() {
+					break
+				}
+			}
+		})
+	}
+	wg.Wait()
+	assert.Equal(t, s.Len(), 800)
+}
+
+func TestSyncSetCopies(t *testing.T) {
+	s := NewSyncSet("a")
+	c := s.Clone()
+	c.Insert("b")
+	assert.Assert(t, !s.Contains("b"))
+
+	plain := s.ToSet()
+	plain.Insert("c")
+	assert.Assert(t, !s.Contains("c"))
+
+	// the set can be modified while iterating
+	for v := range s.All() {
+		s.Delete(v)
+	}
+	assert.Equal(t, s.Len(), 0)
+}
@@ -50,8 +50,13 @@ var logCallsByDefault = false
 // or failure is determined by whether there was a SetCallStatus or
 // not.  (Panics detected in EndCall are considered errors).
 //
+// The call gets its own buffer of debug logs (see log.WithDebugBuffer),
+// shared with nested calls, so that an error only writes out the debug
+// logs of the call rather than those of every concurrent request.
+//
 // StartCalls can be nested.
 func StartCall(ctx context.Context, cType string, args ...log.Marshaler) context.Context {
+	ctx = log.WithDebugBuffer(ctx)
 	log.Debug(ctx, fmt.Sprintf("calling: %s", cType), append(args, IDs(ctx))...)
 
 	// Specify the default behavior first in line.  It might be overridden